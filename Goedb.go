@@ -1,45 +1,85 @@
 package goedb
 
 import (
+	"context"
 	"errors"
-	"fmt"
 	"log"
-	"os"
+	"strings"
 
 	"github.com/plopezm/goedb/database/dbaccess"
 
 	"github.com/plopezm/goedb/config"
 	"github.com/plopezm/goedb/database"
+	"github.com/plopezm/goedb/database/migrations"
+	"github.com/plopezm/goedb/database/models"
 )
 
 var goedbStandalone *dbm
 
 type dbm struct {
-	drivers map[string]database.EntityManager
+	drivers     map[string]database.EntityManager
+	datasources map[string]config.Datasource
 }
 
 func init() {
 	log.Println("[GOEDB] library version: 1.0.0")
 	goedbStandalone = new(dbm)
 	goedbStandalone.drivers = make(map[string]database.EntityManager)
+	goedbStandalone.datasources = make(map[string]config.Datasource)
 }
 
 // Initialize gets the datasources from persistence.json
-func Initialize() {
+func Initialize() error {
+	return InitializeFile("persistence.json")
+}
+
+// InitializeFile gets the datasources from the persistence file received. An error is returned, before
+// opening any connection, if a datasource uses a driver without a dialect registered or has an invalid
+// configuration. If a connection cannot be opened the other datasources are still initialized and an error
+// with every datasource failed is returned
+func InitializeFile(persistenceConfigFile string) error {
 	var persistence config.Persistence
-	persistence = config.GetPersistenceConfig("persistence.json")
+	persistence = config.GetPersistenceConfig(persistenceConfigFile)
 
-	for _, datasource := range persistence.Datasources {
+	drivers := make([]*database.SQLDatabase, len(persistence.Datasources))
+	for i, datasource := range persistence.Datasources {
+		databaseAccess, err := dbaccess.GetDatabaseAccess(datasource.Driver)
+		if err != nil {
+			return errors.New("Persistence unit \"" + datasource.Name + "\": " + err.Error())
+		}
+		statementTimeout, err := datasource.GetStatementTimeout()
+		if err != nil {
+			return errors.New("Persistence unit \"" + datasource.Name + "\": " + err.Error())
+		}
+		naming, err := models.GetNamingStrategy(datasource.Naming, datasource.TablePrefix)
+		if err != nil {
+			return errors.New("Persistence unit \"" + datasource.Name + "\": " + err.Error())
+		}
 		driver := new(database.SQLDatabase)
-		driver.DBAccess = dbaccess.GetDatabaseAccess(datasource.Driver)
-		err := driver.Open(datasource.Driver, datasource.URL, datasource.Schema)
+		driver.DBAccess = databaseAccess
+		driver.Datasource = datasource
+		driver.StatementTimeout = statementTimeout
+		driver.Naming = naming
+		if datasource.Snapshots {
+			driver.Snapshots = database.NewSnapshots()
+		}
+		drivers[i] = driver
+	}
+
+	failed := make([]string, 0)
+	for i, datasource := range persistence.Datasources {
+		err := drivers[i].Open(datasource.Driver, datasource.URL, datasource.Schema)
 		if err != nil {
-			fmt.Fprintf(os.Stdout, "[Connection ERROR for Persistence unit { %s } URL { %s }]: %v\n", datasource.Name, datasource.URL, err)
-			//os.Exit(1)
+			failed = append(failed, "Persistence unit \""+datasource.Name+"\": "+err.Error())
 			continue
 		}
-		goedbStandalone.drivers[datasource.Name] = driver
+		goedbStandalone.drivers[datasource.Name] = drivers[i]
+		goedbStandalone.datasources[datasource.Name] = datasource
 	}
+	if len(failed) > 0 {
+		return errors.New(strings.Join(failed, "; "))
+	}
+	return nil
 }
 
 // GetEntityManager returns a entity manager for the datasource selected.
@@ -51,3 +91,47 @@ func GetEntityManager(persistenceUnit string) (database.EntityManager, error) {
 
 	return entityManager, nil
 }
+
+// MigrateUp applies the pending migrations to the datasource of a persistence unit. The migrations are the ones
+// added with migrations.Register and the files found in the migrations directory of the datasource
+func MigrateUp(persistenceUnit string) error {
+	migrator, err := getMigrator(persistenceUnit)
+	if err != nil {
+		return err
+	}
+	return migrator.Up(context.Background())
+}
+
+// MigrateDown reverts the last steps migrations applied to the datasource of a persistence unit
+func MigrateDown(persistenceUnit string, steps int) error {
+	migrator, err := getMigrator(persistenceUnit)
+	if err != nil {
+		return err
+	}
+	return migrator.Down(context.Background(), steps)
+}
+
+// MigrationStatus returns the state of the migrations in the datasource of a persistence unit
+func MigrationStatus(persistenceUnit string) ([]migrations.Status, error) {
+	migrator, err := getMigrator(persistenceUnit)
+	if err != nil {
+		return nil, err
+	}
+	return migrator.Status(context.Background())
+}
+
+func getMigrator(persistenceUnit string) (*migrations.Migrator, error) {
+	entityManager, err := GetEntityManager(persistenceUnit)
+	if err != nil {
+		return nil, err
+	}
+	registered := migrations.Registered()
+	if dir := goedbStandalone.datasources[persistenceUnit].Migrations; dir != "" {
+		fromDir, err := migrations.FromDir(dir)
+		if err != nil {
+			return nil, err
+		}
+		registered = append(registered, fromDir...)
+	}
+	return migrations.NewMigrator(entityManager.GetDBConnection(), registered)
+}
@@ -1,10 +1,21 @@
 package goedb
 
 import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"io/ioutil"
+	"math"
+	"os"
+	"path/filepath"
+	"strconv"
 	"testing"
+	"time"
 
 	_ "github.com/lib/pq"
 	_ "github.com/mattn/go-sqlite3"
+	"github.com/plopezm/goedb/database"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -20,100 +31,1412 @@ type soldier struct {
 	Troop troop  `goedb:"fk=troop(ID)"`
 }
 
+type mission struct {
+	ID     int    `goedb:"pk,autoincrement"`
+	Name   string `goedb:"unique"`
+	Status string `goedb:"default='pending'"`
+}
+
+type report struct {
+	ID        int       `goedb:"pk,autoincrement"`
+	Name      string    `goedb:"unique"`
+	Date      time.Time `goedb:"index"`
+	CreatedAt time.Time `goedb:"default=CURRENT_TIMESTAMP"`
+}
+
+type volunteer struct {
+	ID       int    `goedb:"pk,autoincrement"`
+	Name     string `goedb:"notnull"`
+	Nickname *string
+	Age      *int
+	Rank     sql.NullString
+	Enlisted *time.Time
+}
+
+type nulls struct {
+	ID  int `goedb:"pk,autoincrement"`
+	S   sql.NullString
+	N64 sql.NullInt64
+	N32 sql.NullInt32
+	N16 sql.NullInt16
+	B   sql.NullByte
+	F   sql.NullFloat64
+	OK  sql.NullBool
+	T   sql.NullTime
+}
+
+// money is stored as a decimal with two digits, using the cents to avoid rounding errors
+type money struct {
+	cents int64
+}
+
+func (m money) Value() (driver.Value, error) {
+	return strconv.FormatFloat(float64(m.cents)/100, 'f', 2, 64), nil
+}
+
+func (m *money) Scan(src interface{}) error {
+	switch value := src.(type) {
+	case float64:
+		m.cents = int64(math.Round(value * 100))
+	case int64:
+		m.cents = value * 100
+	case []byte:
+		return m.parse(string(value))
+	case string:
+		return m.parse(value)
+	default:
+		return errors.New("Cannot scan money")
+	}
+	return nil
+}
+
+func (m *money) parse(value string) error {
+	parsed, err := strconv.ParseFloat(value, 64)
+	m.cents = int64(math.Round(parsed * 100))
+	return err
+}
+
+type rank int
+
+const (
+	private rank = iota
+	sergeant
+	captain
+)
+
+var rankNames = []string{"private", "sergeant", "captain"}
+
+func (r rank) Value() (driver.Value, error) {
+	return rankNames[r], nil
+}
+
+func (r *rank) Scan(src interface{}) error {
+	name, ok := src.(string)
+	if bytes, isBytes := src.([]byte); isBytes {
+		name, ok = string(bytes), true
+	}
+	for i, rankName := range rankNames {
+		if ok && rankName == name {
+			*r = rank(i)
+			return nil
+		}
+	}
+	return errors.New("Cannot scan rank")
+}
+
+type payroll struct {
+	ID     int    `goedb:"pk,autoincrement"`
+	Rank   rank   `goedb:"type=VARCHAR(20)"`
+	Salary money  `goedb:"type=NUMERIC(12,2),notnull"`
+	Bonus  *money `goedb:"type=NUMERIC(12,2)"`
+}
+
+// untypedPayroll has no type annotation for money, a struct whose SQL type is unknown
+type untypedPayroll struct {
+	ID     int `goedb:"pk"`
+	Salary money
+}
+
+type equipment struct {
+	Weapon string   `json:"weapon"`
+	Ammo   int      `json:"ammo"`
+	Extras []string `json:"extras"`
+}
+
+type loadout struct {
+	ID        int `goedb:"pk,autoincrement"`
+	Name      string
+	Equipment equipment              `goedb:"json"`
+	Settings  map[string]interface{} `goedb:"json"`
+	Tags      []string               `goedb:"json"`
+}
+
+type squad struct {
+	SquadID   int    `goedb:"pk,autoincrement"`
+	SquadName string `goedb:"unique"`
+}
+
+type squadMember struct {
+	MemberID int    `goedb:"pk,autoincrement"`
+	FullName string `goedb:"column=name"`
+	Squad    squad  `goedb:"fk=squad(SquadID)"`
+}
+
+type order struct {
+	ID    int    `goedb:"pk,autoincrement"`
+	Group string `goedb:"index"`
+}
+
+type orderLine struct {
+	ID     int    `goedb:"pk,autoincrement"`
+	Select string `goedb:"unique"`
+	Order  order  `goedb:"fk=order(ID)"`
+}
+
+type upsertSoldier struct {
+	ID     int    `goedb:"pk,autoincrement"`
+	Name   string `goedb:"unique"`
+	Rank   string
+	Status string `goedb:"default='active'"`
+}
+
+type trackedSoldier struct {
+	database.Tracked
+	ID       int    `goedb:"pk,autoincrement"`
+	Name     string `goedb:"unique"`
+	Rank     string
+	Status   string `goedb:"default='active'"`
+	Nickname *string
+}
+
+type versionedSoldier struct {
+	ID      int    `goedb:"pk,autoincrement"`
+	Name    string `goedb:"unique"`
+	Rank    string
+	Version int `goedb:"version"`
+}
+
+type stampedSoldier struct {
+	ID        int    `goedb:"pk,autoincrement"`
+	Name      string `goedb:"unique"`
+	Rank      string
+	CreatedAt time.Time  `goedb:"createdAt"`
+	UpdatedAt *time.Time `goedb:"updatedAt"`
+}
+
+type softTroop struct {
+	ID        int        `goedb:"pk,autoincrement"`
+	Name      string     `goedb:"unique"`
+	DeletedAt *time.Time `goedb:"softDelete"`
+}
+
+type softSoldier struct {
+	ID      int       `goedb:"pk,autoincrement"`
+	Name    string    `goedb:"unique"`
+	Troop   softTroop `goedb:"fk=softTroop(ID)"`
+	Removed bool      `goedb:"softDelete"`
+}
+
+type hookedSoldier struct {
+	ID      int    `goedb:"pk,autoincrement"`
+	Name    string `goedb:"unique"`
+	Rank    string
+	Display string `goedb:"ignore"`
+}
+
+var hookCalls []string
+
+func (soldier *hookedSoldier) BeforeInsert(em database.EntityManager) error {
+	hookCalls = append(hookCalls, "BeforeInsert")
+	if soldier.Name == "" {
+		return errors.New("The name of the soldier is required")
+	}
+	if soldier.Rank == "" {
+		soldier.Rank = "Private"
+	}
+	return nil
+}
+
+func (soldier *hookedSoldier) AfterInsert(em database.EntityManager) error {
+	hookCalls = append(hookCalls, "AfterInsert")
+	return nil
+}
+
+func (soldier *hookedSoldier) BeforeUpdate(em database.EntityManager) error {
+	hookCalls = append(hookCalls, "BeforeUpdate")
+	if soldier.Name == "" {
+		return errors.New("The name of the soldier is required")
+	}
+	return nil
+}
+
+func (soldier *hookedSoldier) AfterUpdate(em database.EntityManager) error {
+	hookCalls = append(hookCalls, "AfterUpdate")
+	return nil
+}
+
+func (soldier *hookedSoldier) BeforeRemove(em database.EntityManager) error {
+	hookCalls = append(hookCalls, "BeforeRemove")
+	if soldier.Rank == "General" {
+		return errors.New("A general cannot be removed")
+	}
+	return nil
+}
+
+func (soldier *hookedSoldier) AfterRemove(em database.EntityManager) error {
+	hookCalls = append(hookCalls, "AfterRemove")
+	return nil
+}
+
+func (soldier *hookedSoldier) AfterFind(em database.EntityManager) error {
+	soldier.Display = soldier.Rank + " " + soldier.Name
+	return nil
+}
+
+type auditEntry struct {
+	ID      int `goedb:"pk,autoincrement"`
+	Soldier string
+}
+
+type auditedSoldier struct {
+	ID      int    `goedb:"pk,autoincrement"`
+	Name    string `goedb:"unique"`
+	Entries int    `goedb:"ignore"`
+}
+
+func (soldier *auditedSoldier) BeforeInsert(em database.EntityManager) error {
+	_, err := em.Insert(&auditEntry{Soldier: soldier.Name})
+	return err
+}
+
+func (soldier *auditedSoldier) AfterFind(em database.EntityManager) error {
+	entries := make([]auditEntry, 0)
+	if err := em.Find(&entries, "auditEntry.Soldier = :name", map[string]interface{}{"name": soldier.Name}); err != nil {
+		return err
+	}
+	soldier.Entries = len(entries)
+	return nil
+}
+
 type testCustomSoldier struct {
 	ID        int
 	Name      string
 	TroopName string
 }
 
-const persistenceUnitItComplexTest = "testSQLite3"
+const persistenceUnitItComplexTest = "testSQLite3"
+
+func init() {
+	Initialize()
+}
+
+func Test_Goedb_Open_Fail(t *testing.T) {
+	_, err := GetEntityManager("Not-exists")
+	assert.NotNil(t, err)
+}
+
+func Test_Goedb_Initialize_Driver_Without_Dialect(t *testing.T) {
+	dir, err := ioutil.TempDir("", "goedb")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+	file := filepath.Join(dir, "persistence.json")
+	persistence := `{"datasources":[{"name":"noDialect","driver":"sqlserver","url":"sqlserver://localhost"}]}`
+	assert.Nil(t, ioutil.WriteFile(file, []byte(persistence), 0644))
+
+	err = InitializeFile(file)
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "sqlserver")
+	_, err = GetEntityManager("noDialect")
+	assert.NotNil(t, err)
+}
+
+func Test_Goedb_Initialize_Errors(t *testing.T) {
+	dir, err := ioutil.TempDir("", "goedb")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+	file := filepath.Join(dir, "persistence.json")
+
+	for name, datasource := range map[string]string{
+		"badTimeout": `"statementTimeout":"soon"`,
+		"badNaming":  `"naming":"camelCase"`,
+	} {
+		persistence := `{"datasources":[{"name":"` + name + `","driver":"sqlite3","url":"` + filepath.Join(dir, "test.db") + `",` + datasource + `}]}`
+		assert.Nil(t, ioutil.WriteFile(file, []byte(persistence), 0644))
+		err = InitializeFile(file)
+		assert.NotNil(t, err)
+		assert.Contains(t, err.Error(), name)
+		_, err = GetEntityManager(name)
+		assert.NotNil(t, err)
+	}
+
+	persistence := `{"datasources":[{"name":"unreachable","driver":"sqlite3","url":"` + filepath.Join(dir, "notfound", "test.db") + `"},` +
+		`{"name":"reachable","driver":"sqlite3","url":"` + filepath.Join(dir, "test.db") + `"}]}`
+	assert.Nil(t, ioutil.WriteFile(file, []byte(persistence), 0644))
+	err = InitializeFile(file)
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "unreachable")
+	_, err = GetEntityManager("unreachable")
+	assert.NotNil(t, err)
+	em, err := GetEntityManager("reachable")
+	assert.Nil(t, err)
+	assert.Nil(t, em.Close())
+}
+
+func Test_Goedb_Open_And_Close(t *testing.T) {
+	em, err := GetEntityManager("closeTest")
+	assert.Nil(t, err)
+
+	tx, err := em.TxBegin()
+	assert.Nil(t, err)
+	assert.NotNil(t, tx)
+
+	tx.Rollback()
+
+	err = em.Close()
+	assert.Nil(t, err)
+}
+
+func Test_Goedb_Migrate(t *testing.T) {
+	em, err := GetEntityManager(persistenceUnitItComplexTest)
+	assert.Nil(t, err)
+	assert.NotNil(t, em)
+
+	err = em.Migrate(&troop{}, true, true)
+	assert.Nil(t, err)
+
+	err = em.Migrate(&soldier{}, true, true)
+	assert.Nil(t, err)
+}
+
+func Test_Goedb_Migrate_Recreate(t *testing.T) {
+	em, err := GetEntityManager(persistenceUnitItComplexTest)
+	assert.Nil(t, err)
+	assert.NotNil(t, em)
+
+	err = em.Migrate(&troop{}, true, false)
+	assert.Nil(t, err)
+
+	err = em.Migrate(&soldier{}, true, false)
+	assert.Nil(t, err)
+}
+
+func Test_Goedb_Migrate_Alter(t *testing.T) {
+	em, err := GetEntityManager(persistenceUnitItComplexTest)
+	assert.Nil(t, err)
+	assert.NotNil(t, em)
+
+	type briefing struct {
+		ID      int     `goedb:"pk,autoincrement"`
+		Mission mission `goedb:"fk=mission(ID)"`
+	}
+
+	err = em.Migrate(&mission{}, true, true)
+	assert.Nil(t, err)
+	err = em.Migrate(&briefing{}, true, true)
+	assert.Nil(t, err)
+
+	mission1 := &mission{Name: "Normandy"}
+	_, err = em.Insert(mission1)
+	assert.Nil(t, err)
+	_, err = em.Insert(&briefing{Mission: *mission1})
+	assert.Nil(t, err)
+
+	{
+		type mission struct {
+			ID       int    `goedb:"pk,autoincrement"`
+			Name     string `goedb:"unique"`
+			Status   string `goedb:"default='pending'"`
+			Priority int    `goedb:"default=1"`
+			Code     string `goedb:"default='',index"`
+		}
+		err = em.Migrate(&mission{}, true, false)
+		assert.Nil(t, err)
+
+		found := &mission{ID: mission1.ID}
+		err = em.First(found, "", nil)
+		assert.Nil(t, err)
+		assert.Equal(t, "Normandy", found.Name)
+		assert.Equal(t, 1, found.Priority)
+
+		var indexes int
+		err = em.GetDBConnection().Get(&indexes, "SELECT COUNT(*) FROM sqlite_master WHERE type = 'index' AND name = 'idx_mission_Code'")
+		assert.Nil(t, err)
+		assert.Equal(t, 1, indexes)
+	}
+
+	{
+		type mission struct {
+			ID       int    `goedb:"pk,autoincrement"`
+			Name     string `goedb:"unique"`
+			Status   string `goedb:"default='pending'"`
+			Priority int    `goedb:"default=1"`
+			Code     string `goedb:"default='',unique,index"`
+		}
+		err = em.Migrate(&mission{}, true, false)
+		assert.Nil(t, err)
+
+		found := &mission{ID: mission1.ID}
+		err = em.First(found, "", nil)
+		assert.Nil(t, err)
+		assert.Equal(t, "Normandy", found.Name)
+		assert.Equal(t, 1, found.Priority)
+
+		_, err = em.Insert(&mission{Name: "Overlord", Code: "D"})
+		assert.Nil(t, err)
+		_, err = em.Insert(&mission{Name: "Market Garden", Code: "D"})
+		assert.NotNil(t, err)
+
+		var briefings int
+		err = em.GetDBConnection().Get(&briefings, "SELECT COUNT(*) FROM briefing")
+		assert.Nil(t, err)
+		assert.Equal(t, 1, briefings)
+
+		err = em.Migrate(&mission{}, true, false)
+		assert.Nil(t, err)
+	}
+
+	err = em.DropTable(&briefing{})
+	assert.Nil(t, err)
+	err = em.DropTable(&mission{})
+	assert.Nil(t, err)
+}
+
+func Test_Goedb_Model(t *testing.T) {
+	em, err := GetEntityManager(persistenceUnitItComplexTest)
+	assert.Nil(t, err)
+	assert.NotNil(t, em)
+
+	soldier1, err := em.Model(&soldier{})
+	assert.Nil(t, err)
+	assert.Equal(t, "soldier", soldier1.Name)
+
+	troop1, err := em.Model(&troop{})
+	assert.Nil(t, err)
+	assert.Equal(t, "troop", troop1.Name)
+}
+
+func Test_Goedb_Insert(t *testing.T) {
+	em, err := GetEntityManager(persistenceUnitItComplexTest)
+	assert.Nil(t, err)
+	assert.NotNil(t, em)
+
+	troop1 := &troop{
+		Name: "TheBestTeam",
+	}
+
+	soldier1 := &soldier{
+		Name:  "Ryan",
+		Troop: *troop1,
+	}
+
+	result, err := em.Insert(troop1)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, troop1.ID)
+	assert.Equal(t, int64(1), result.LastInsertId)
+
+	soldier1.Troop.ID = 1
+
+	_, err = em.Insert(soldier1)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, soldier1.ID)
+
+	soldier1.Troop.ID = 2
+	_, err = em.Insert(soldier1)
+	assert.NotNil(t, err)
+}
+
+func Test_Goedb_Insert_Generated_Values(t *testing.T) {
+	em, err := GetEntityManager(persistenceUnitItComplexTest)
+	assert.Nil(t, err)
+	assert.NotNil(t, em)
+
+	err = em.Migrate(&mission{}, true, true)
+	assert.Nil(t, err)
+
+	mission1 := &mission{Name: "Normandy"}
+	_, err = em.Insert(mission1)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, mission1.ID)
+	assert.Equal(t, "pending", mission1.Status)
+
+	mission2 := &mission{Name: "Overlord", Status: "done"}
+	_, err = em.Insert(mission2)
+	assert.Nil(t, err)
+	assert.Equal(t, 2, mission2.ID)
+	assert.Equal(t, "done", mission2.Status)
+
+	err = em.DropTable(&mission{})
+	assert.Nil(t, err)
+}
+
+func Test_Goedb_Time_Columns(t *testing.T) {
+	em, err := GetEntityManager(persistenceUnitItComplexTest)
+	assert.Nil(t, err)
+	assert.NotNil(t, em)
+
+	err = em.Migrate(&report{}, true, true)
+	assert.Nil(t, err)
+
+	madrid := time.FixedZone("CET", 3600)
+	report1 := &report{Name: "Normandy", Date: time.Date(1944, 6, 6, 6, 30, 0, 0, madrid)}
+	_, err = em.Insert(report1)
+	assert.Nil(t, err)
+	assert.False(t, report1.CreatedAt.IsZero())
+	assert.Equal(t, time.UTC, report1.CreatedAt.Location())
+
+	report2 := &report{Name: "Overlord", Date: time.Date(1944, 6, 6, 5, 0, 0, 0, time.UTC)}
+	_, err = em.Insert(report2)
+	assert.Nil(t, err)
+
+	found := &report{ID: report1.ID}
+	err = em.First(found, "", nil)
+	assert.Nil(t, err)
+	assert.True(t, report1.Date.Equal(found.Date))
+	assert.Equal(t, time.UTC, found.Date.Location())
+
+	reports := make([]report, 0)
+	err = em.Query(&reports).
+		Where("report.Date > :from", map[string]interface{}{"from": time.Date(1944, 6, 6, 6, 15, 0, 0, madrid)}).
+		OrderBy("report.Date").
+		Find()
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(reports))
+	assert.Equal(t, "Normandy", reports[0].Name)
+
+	reports = make([]report, 0)
+	err = em.Query(&reports).OrderBy("report.Date").Find()
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(reports))
+	assert.Equal(t, "Overlord", reports[0].Name)
+	assert.Equal(t, "Normandy", reports[1].Name)
+
+	err = em.DropTable(&report{})
+	assert.Nil(t, err)
+}
+
+func Test_Goedb_Nullable_Columns(t *testing.T) {
+	em, err := GetEntityManager(persistenceUnitItComplexTest)
+	assert.Nil(t, err)
+	assert.NotNil(t, em)
+
+	err = em.Migrate(&volunteer{}, true, true)
+	assert.Nil(t, err)
+
+	volunteer1 := &volunteer{Name: "Ryan"}
+	_, err = em.Insert(volunteer1)
+	assert.Nil(t, err)
+
+	found := &volunteer{ID: volunteer1.ID}
+	err = em.First(found, "", nil)
+	assert.Nil(t, err)
+	assert.Nil(t, found.Nickname)
+	assert.Nil(t, found.Age)
+	assert.False(t, found.Rank.Valid)
+	assert.Nil(t, found.Enlisted)
+
+	nickname, age, enlisted := "Private", 21, time.Date(1944, 6, 6, 6, 30, 0, 0, time.UTC)
+	found.Nickname, found.Age, found.Enlisted = &nickname, &age, &enlisted
+	found.Rank = sql.NullString{String: "Private first class", Valid: true}
+	_, err = em.Update(found)
+	assert.Nil(t, err)
+
+	updated := &volunteer{ID: volunteer1.ID}
+	err = em.First(updated, "", nil)
+	assert.Nil(t, err)
+	assert.Equal(t, "Private", *updated.Nickname)
+	assert.Equal(t, 21, *updated.Age)
+	assert.Equal(t, "Private first class", updated.Rank.String)
+	assert.True(t, enlisted.Equal(*updated.Enlisted))
+
+	updated.Nickname, updated.Enlisted = nil, nil
+	_, err = em.Update(updated)
+	assert.Nil(t, err)
+
+	volunteers := make([]volunteer, 0)
+	err = em.Find(&volunteers, "volunteer.Nickname IS NULL", nil)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(volunteers))
+	assert.Nil(t, volunteers[0].Enlisted)
+	assert.Equal(t, 21, *volunteers[0].Age)
+
+	_, err = em.GetDBConnection().Exec("INSERT INTO volunteer (Name) VALUES (NULL)")
+	assert.NotNil(t, err)
+
+	err = em.DropTable(&volunteer{})
+	assert.Nil(t, err)
+}
+
+func Test_Goedb_Null_Types(t *testing.T) {
+	em, err := GetEntityManager(persistenceUnitItComplexTest)
+	assert.Nil(t, err)
+	assert.NotNil(t, em)
+
+	assert.Nil(t, em.Migrate(&nulls{}, true, true))
+
+	empty := &nulls{}
+	_, err = em.Insert(empty)
+	assert.Nil(t, err)
+	found := &nulls{ID: empty.ID}
+	assert.Nil(t, em.First(found, "", nil))
+	assert.Equal(t, nulls{ID: empty.ID}, *found)
+
+	now := time.Date(1944, 6, 6, 6, 30, 0, 0, time.FixedZone("CET", 3600))
+	full := &nulls{
+		S:   sql.NullString{String: "Ryan", Valid: true},
+		N64: sql.NullInt64{Int64: 1 << 40, Valid: true},
+		N32: sql.NullInt32{Int32: 1 << 20, Valid: true},
+		N16: sql.NullInt16{Int16: 1 << 10, Valid: true},
+		B:   sql.NullByte{Byte: 255, Valid: true},
+		F:   sql.NullFloat64{Float64: 1.5, Valid: true},
+		OK:  sql.NullBool{Bool: true, Valid: true},
+		T:   sql.NullTime{Time: now, Valid: true},
+	}
+	_, err = em.Insert(full)
+	assert.Nil(t, err)
+	found = &nulls{ID: full.ID}
+	assert.Nil(t, em.First(found, "", nil))
+	assert.True(t, now.Equal(found.T.Time))
+	found.T, full.T = sql.NullTime{}, sql.NullTime{}
+	assert.Equal(t, *full, *found)
+
+	assert.Nil(t, em.DropTable(&nulls{}))
+}
+
+func Test_Goedb_Custom_Types(t *testing.T) {
+	em, err := GetEntityManager(persistenceUnitItComplexTest)
+	assert.Nil(t, err)
+	assert.NotNil(t, em)
+
+	err = em.Migrate(&payroll{}, true, true)
+	assert.Nil(t, err)
+
+	payroll1 := &payroll{Rank: sergeant, Salary: money{cents: 123456}}
+	_, err = em.Insert(payroll1)
+	assert.Nil(t, err)
+	payroll2 := &payroll{Rank: captain, Salary: money{cents: 250010}, Bonus: &money{cents: 5025}}
+	_, err = em.Insert(payroll2)
+	assert.Nil(t, err)
+
+	var storedRank string
+	err = em.GetDBConnection().Get(&storedRank, "SELECT Rank FROM payroll WHERE ID = ?", payroll1.ID)
+	assert.Nil(t, err)
+	assert.Equal(t, "sergeant", storedRank)
+
+	found := &payroll{}
+	err = em.First(found, "payroll.Rank = :rank", map[string]interface{}{"rank": captain})
+	assert.Nil(t, err)
+	assert.Equal(t, captain, found.Rank)
+	assert.Equal(t, int64(250010), found.Salary.cents)
+	assert.Equal(t, int64(5025), found.Bonus.cents)
+
+	found.Salary = money{cents: 260000}
+	_, err = em.Update(found)
+	assert.Nil(t, err)
+
+	payrolls := make([]payroll, 0)
+	err = em.Query(&payrolls).OrderBy("payroll.Salary DESC").Find()
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(payrolls))
+	assert.Equal(t, int64(260000), payrolls[0].Salary.cents)
+	assert.Equal(t, sergeant, payrolls[1].Rank)
+	assert.Nil(t, payrolls[1].Bonus)
+
+	err = em.DropTable(&payroll{})
+	assert.Nil(t, err)
+
+	err = em.Migrate(&untypedPayroll{}, true, true)
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "Salary")
+}
+
+func Test_Goedb_JSON_Columns(t *testing.T) {
+	em, err := GetEntityManager(persistenceUnitItComplexTest)
+	assert.Nil(t, err)
+	assert.NotNil(t, em)
+
+	err = em.Migrate(&loadout{}, true, true)
+	assert.Nil(t, err)
+
+	sniper := &loadout{
+		Name:      "sniper",
+		Equipment: equipment{Weapon: "rifle", Ammo: 20, Extras: []string{"scope"}},
+		Settings:  map[string]interface{}{"camo": "forest"},
+		Tags:      []string{"ranged", "stealth"},
+	}
+	_, err = em.Insert(sniper)
+	assert.Nil(t, err)
+	_, err = em.Insert(&loadout{Name: "assault", Equipment: equipment{Weapon: "carbine", Ammo: 120}})
+	assert.Nil(t, err)
+
+	var stored string
+	err = em.GetDBConnection().Get(&stored, "SELECT Tags FROM loadout WHERE ID = ?", sniper.ID)
+	assert.Nil(t, err)
+	assert.Equal(t, `["ranged","stealth"]`, stored)
+
+	found := &loadout{ID: sniper.ID}
+	err = em.First(found, "", nil)
+	assert.Nil(t, err)
+	assert.Equal(t, sniper.Equipment, found.Equipment)
+	assert.Equal(t, "forest", found.Settings["camo"])
+	assert.Equal(t, sniper.Tags, found.Tags)
+
+	loadouts := make([]loadout, 0)
+	err = em.Query(&loadouts).WhereJSON("loadout.Equipment", "ammo", ">", 50).Find()
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(loadouts))
+	assert.Equal(t, "assault", loadouts[0].Name)
+	assert.Nil(t, loadouts[0].Settings)
+	assert.Nil(t, loadouts[0].Tags)
+
+	loadouts = make([]loadout, 0)
+	err = em.Query(&loadouts).WhereJSON("loadout.Equipment", "extras.0", "=", "scope").WhereJSON("loadout.Settings", "camo", "=", "forest").Find()
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(loadouts))
+	assert.Equal(t, "sniper", loadouts[0].Name)
+
+	err = em.Query(&loadouts).WhereJSON("loadout.Equipment", "ammo') --", "=", 1).Find()
+	assert.NotNil(t, err)
+
+	err = em.DropTable(&loadout{})
+	assert.Nil(t, err)
+}
+
+func Test_Goedb_Naming_Strategy(t *testing.T) {
+	em, err := GetEntityManager("testSQLite3SnakeCase")
+	assert.Nil(t, err)
+	assert.NotNil(t, em)
+
+	err = em.Migrate(&squad{}, true, true)
+	assert.Nil(t, err)
+	err = em.Migrate(&squadMember{}, true, true)
+	assert.Nil(t, err)
+
+	tables, err := em.Tables()
+	assert.Nil(t, err)
+	columns := make([]string, 0)
+	for _, table := range tables {
+		if table.Name == "squad_member" {
+			for _, column := range table.Columns {
+				columns = append(columns, column.Name)
+			}
+		}
+	}
+	assert.Equal(t, []string{"member_id", "name", "squad"}, columns)
+
+	alpha := &squad{SquadName: "alpha"}
+	_, err = em.Insert(alpha)
+	assert.Nil(t, err)
+	member := &squadMember{FullName: "Ryan", Squad: *alpha}
+	_, err = em.Insert(member)
+	assert.Nil(t, err)
+
+	found := &squadMember{MemberID: member.MemberID}
+	err = em.First(found, "", nil)
+	assert.Nil(t, err)
+	assert.Equal(t, "Ryan", found.FullName)
+	assert.Equal(t, "alpha", found.Squad.SquadName)
+
+	found.FullName = "Miller"
+	_, err = em.Update(found)
+	assert.Nil(t, err)
+
+	members := make([]squadMember, 0)
+	err = em.Query(&members).Where("squad_member.name = :name", map[string]interface{}{"name": "Miller"}).Find()
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(members))
+	assert.Equal(t, alpha.SquadID, members[0].Squad.SquadID)
+
+	result, err := em.Remove(&squadMember{}, "squad_member.name = :name", map[string]interface{}{"name": "Miller"})
+	assert.Nil(t, err)
+	assert.Equal(t, int64(1), result.NumRecordsAffected)
+
+	assert.Nil(t, em.DropTable(&squadMember{}))
+	assert.Nil(t, em.DropTable(&squad{}))
+}
+
+func Test_Goedb_Reserved_Words(t *testing.T) {
+	em, err := GetEntityManager(persistenceUnitItComplexTest)
+	assert.Nil(t, err)
+	assert.NotNil(t, em)
+
+	err = em.Migrate(&order{}, true, true)
+	assert.Nil(t, err)
+	err = em.Migrate(&orderLine{}, true, true)
+	assert.Nil(t, err)
+
+	order1 := &order{Group: "first"}
+	_, err = em.Insert(order1)
+	assert.Nil(t, err)
+	line := &orderLine{Select: "line1", Order: *order1}
+	_, err = em.Insert(line)
+	assert.Nil(t, err)
+
+	found := &orderLine{ID: line.ID}
+	err = em.First(found, "", nil)
+	assert.Nil(t, err)
+	assert.Equal(t, "line1", found.Select)
+	assert.Equal(t, "first", found.Order.Group)
+
+	found.Select = "line2"
+	_, err = em.Update(found)
+	assert.Nil(t, err)
+
+	lines := make([]orderLine, 0)
+	err = em.Query(&lines).Where(`"orderLine"."Select" = :select`, map[string]interface{}{"select": "line2"}).Find()
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(lines))
+
+	_, err = em.Remove(&orderLine{ID: line.ID}, "", nil)
+	assert.Nil(t, err)
+	assert.Nil(t, em.DropTable(&orderLine{}))
+	assert.Nil(t, em.DropTable(&order{}))
+}
+
+func Test_Goedb_Upsert(t *testing.T) {
+	em, err := GetEntityManager(persistenceUnitItComplexTest)
+	assert.Nil(t, err)
+	assert.NotNil(t, em)
+
+	err = em.Migrate(&upsertSoldier{}, true, true)
+	assert.Nil(t, err)
+
+	ryan := &upsertSoldier{Name: "Ryan", Rank: "Private"}
+	result, err := em.Upsert(ryan)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(1), result.NumRecordsAffected)
+	assert.NotEqual(t, 0, ryan.ID)
+	assert.Equal(t, "active", ryan.Status)
+
+	promoted := &upsertSoldier{Name: "Ryan", Rank: "Sergeant"}
+	_, err = em.Upsert(promoted, "Name")
+	assert.Nil(t, err)
+	assert.Equal(t, ryan.ID, promoted.ID)
+
+	soldiers := make([]upsertSoldier, 0)
+	err = em.Find(&soldiers, "", nil)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(soldiers))
+	assert.Equal(t, "Sergeant", soldiers[0].Rank)
+
+	byID := &upsertSoldier{ID: ryan.ID, Name: "Ryan", Rank: "Captain"}
+	_, err = em.Upsert(byID)
+	assert.Nil(t, err)
+	soldiers = make([]upsertSoldier, 0)
+	assert.Nil(t, em.Find(&soldiers, "", nil))
+	assert.Equal(t, 1, len(soldiers))
+	assert.Equal(t, "Captain", soldiers[0].Rank)
+
+	_, err = em.Upsert(&upsertSoldier{Name: "Miller"}, "Rank")
+	assert.NotNil(t, err)
+
+	assert.Nil(t, em.DropTable(&upsertSoldier{}))
+}
+
+func Test_Goedb_InsertAll(t *testing.T) {
+	em, err := GetEntityManager(persistenceUnitItComplexTest)
+	assert.Nil(t, err)
+	assert.NotNil(t, em)
+
+	err = em.Migrate(&upsertSoldier{}, true, true)
+	assert.Nil(t, err)
+
+	soldiers := make([]upsertSoldier, 0)
+	for i := 0; i < 1200; i++ {
+		soldiers = append(soldiers, upsertSoldier{Name: "Soldier" + strconv.Itoa(i), Rank: "Private"})
+	}
+	result, err := em.InsertAll(soldiers)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(1200), result.NumRecordsAffected)
+	assert.Equal(t, int64(soldiers[1199].ID), result.LastInsertId)
+	for i := 1; i < len(soldiers); i++ {
+		assert.Equal(t, soldiers[i-1].ID+1, soldiers[i].ID)
+	}
+
+	found := &upsertSoldier{ID: soldiers[600].ID}
+	err = em.First(found, "", nil)
+	assert.Nil(t, err)
+	assert.Equal(t, "Soldier600", found.Name)
+	assert.Equal(t, "active", found.Status)
+
+	duplicated := []*upsertSoldier{{Name: "New"}, {Name: "Soldier0"}}
+	_, err = em.InsertAll(&duplicated)
+	assert.NotNil(t, err)
+	err = em.First(&upsertSoldier{}, "upsertSoldier.Name = :name", map[string]interface{}{"name": "New"})
+	assert.NotNil(t, err)
+
+	_, err = em.InsertAll(upsertSoldier{})
+	assert.NotNil(t, err)
+
+	assert.Nil(t, em.DropTable(&upsertSoldier{}))
+}
+
+func Test_Goedb_UpdateWhere_RemoveWhere(t *testing.T) {
+	em, err := GetEntityManager(persistenceUnitItComplexTest)
+	assert.Nil(t, err)
+	assert.NotNil(t, em)
+
+	err = em.Migrate(&upsertSoldier{}, true, true)
+	assert.Nil(t, err)
+
+	soldiers := []upsertSoldier{
+		{Name: "Ryan", Rank: "Private"},
+		{Name: "Miller", Rank: "Captain"},
+		{Name: "Reiben", Rank: "Private"},
+		{Name: "Jackson", Rank: "Private"},
+	}
+	_, err = em.InsertAll(soldiers)
+	assert.Nil(t, err)
+
+	result, err := em.UpdateWhere(&upsertSoldier{}, map[string]interface{}{"Status": "retired", "Rank": "Corporal"}, "upsertSoldier.Rank = :rank", map[string]interface{}{"rank": "Private"})
+	assert.Nil(t, err)
+	assert.Equal(t, int64(3), result.NumRecordsAffected)
+
+	found := make([]upsertSoldier, 0)
+	err = em.Find(&found, "upsertSoldier.Status = :status", map[string]interface{}{"status": "retired"})
+	assert.Nil(t, err)
+	assert.Equal(t, 3, len(found))
+	for _, soldier := range found {
+		assert.Equal(t, "Corporal", soldier.Rank)
+	}
+
+	_, err = em.UpdateWhere(&upsertSoldier{}, map[string]interface{}{"Unknown": 1}, "upsertSoldier.Rank = :rank", map[string]interface{}{"rank": "Captain"})
+	assert.NotNil(t, err)
+	_, err = em.UpdateWhere(&upsertSoldier{}, map[string]interface{}{"Status": "retired"}, "", nil)
+	assert.NotNil(t, err)
+
+	result, err = em.RemoveWhere(&upsertSoldier{}, "upsertSoldier.Status = :status AND upsertSoldier.Name <> :name", map[string]interface{}{"status": "retired", "name": "Ryan"})
+	assert.Nil(t, err)
+	assert.Equal(t, int64(2), result.NumRecordsAffected)
+
+	_, err = em.RemoveWhere(&upsertSoldier{}, "", nil)
+	assert.NotNil(t, err)
+
+	found = make([]upsertSoldier, 0)
+	err = em.Find(&found, "", nil)
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(found))
+
+	assert.Nil(t, em.DropTable(&upsertSoldier{}))
+}
+
+func Test_Goedb_UpdateFields(t *testing.T) {
+	em, err := GetEntityManager(persistenceUnitItComplexTest)
+	assert.Nil(t, err)
+	assert.NotNil(t, em)
 
-func init() {
-	Initialize()
+	err = em.Migrate(&upsertSoldier{}, true, true)
+	assert.Nil(t, err)
+
+	ryan := &upsertSoldier{Name: "Ryan", Rank: "Private"}
+	_, err = em.Insert(ryan)
+	assert.Nil(t, err)
+
+	ryan.Name = "James Ryan"
+	ryan.Rank = "Corporal"
+	result, err := em.UpdateFields(ryan, "Rank")
+	assert.Nil(t, err)
+	assert.Equal(t, int64(1), result.NumRecordsAffected)
+
+	found := &upsertSoldier{ID: ryan.ID}
+	err = em.First(found, "", nil)
+	assert.Nil(t, err)
+	assert.Equal(t, "Ryan", found.Name)
+	assert.Equal(t, "Corporal", found.Rank)
+
+	_, err = em.UpdateFields(ryan, "ID")
+	assert.NotNil(t, err)
+	_, err = em.UpdateFields(ryan, "Unknown")
+	assert.NotNil(t, err)
+	_, err = em.UpdateFields(ryan)
+	assert.NotNil(t, err)
+
+	assert.Nil(t, em.DropTable(&upsertSoldier{}))
 }
 
-func Test_Goedb_Open_Fail(t *testing.T) {
-	_, err := GetEntityManager("Not-exists")
+func Test_Goedb_Hooks_Transaction(t *testing.T) {
+	em, err := GetEntityManager(persistenceUnitItComplexTest)
+	assert.Nil(t, err)
+	assert.NotNil(t, em)
+
+	assert.Nil(t, em.Migrate(&auditEntry{}, true, true))
+	assert.Nil(t, em.Migrate(&auditedSoldier{}, true, true))
+
+	_, err = em.InsertAll([]auditedSoldier{{Name: "Ryan"}, {Name: "Ryan"}})
 	assert.NotNil(t, err)
+	assert.NotNil(t, em.Find(&[]auditEntry{}, "", nil))
+
+	_, err = em.Insert(&auditedSoldier{Name: "Ryan"})
+	assert.Nil(t, err)
+	err = em.Transaction(func(tx database.EntityManager) error {
+		found := &auditedSoldier{}
+		if err := tx.First(found, "auditedSoldier.Name = :name", map[string]interface{}{"name": "Ryan"}); err != nil {
+			return err
+		}
+		assert.Equal(t, 1, found.Entries)
+		soldiers := make([]auditedSoldier, 0)
+		if err := tx.Find(&soldiers, "", nil); err != nil {
+			return err
+		}
+		assert.Equal(t, 1, soldiers[0].Entries)
+		return nil
+	})
+	assert.Nil(t, err)
+
+	assert.Nil(t, em.DropTable(&auditedSoldier{}))
+	assert.Nil(t, em.DropTable(&auditEntry{}))
 }
 
-func Test_Goedb_Open_And_Close(t *testing.T) {
-	em, err := GetEntityManager("closeTest")
+func Test_Goedb_Find_Scan_Errors(t *testing.T) {
+	em, err := GetEntityManager(persistenceUnitItComplexTest)
+	assert.Nil(t, err)
+	assert.NotNil(t, em)
+
+	err = em.Migrate(&upsertSoldier{}, true, true)
+	assert.Nil(t, err)
+	_, err = em.GetDBConnection().Exec(`INSERT INTO "upsertSoldier" ("Name", "Rank") VALUES ('Ryan', NULL)`)
+	assert.Nil(t, err)
+
+	assert.NotNil(t, em.First(&upsertSoldier{}, "", nil))
+	soldiers := make([]upsertSoldier, 0)
+	assert.NotNil(t, em.Find(&soldiers, "", nil))
+	soldiers = make([]upsertSoldier, 0)
+	assert.NotNil(t, em.Query(&soldiers).Find())
+	soldiers = make([]upsertSoldier, 0)
+	assert.NotNil(t, em.NativeFind(&soldiers, `SELECT "ID", "Name", "Rank", "Status" FROM "upsertSoldier"`, nil))
+
+	assert.Nil(t, em.DropTable(&upsertSoldier{}))
+}
+
+func Test_Goedb_Update_Snapshots(t *testing.T) {
+	em, err := GetEntityManager("testSQLite3Snapshots")
+	assert.Nil(t, err)
+	assert.NotNil(t, em)
+
+	err = em.Migrate(&trackedSoldier{}, true, true)
+	assert.Nil(t, err)
+
+	ryan := &trackedSoldier{Name: "Ryan", Rank: "Private"}
+	_, err = em.Insert(ryan)
+	assert.Nil(t, err)
+
+	first := &trackedSoldier{ID: ryan.ID}
+	assert.Nil(t, em.First(first, "", nil))
+	second := make([]trackedSoldier, 0)
+	assert.Nil(t, em.Find(&second, "", nil))
+	assert.Equal(t, 1, len(second))
+
+	first.Rank = "Sergeant"
+	result, err := em.Update(first)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(1), result.NumRecordsAffected)
+
+	second[0].Status = "retired"
+	result, err = em.Update(&second[0])
+	assert.Nil(t, err)
+	assert.Equal(t, int64(1), result.NumRecordsAffected)
+
+	result, err = em.Update(&second[0])
 	assert.Nil(t, err)
+	assert.Equal(t, int64(0), result.NumRecordsAffected)
+
+	found := &trackedSoldier{ID: ryan.ID}
+	assert.Nil(t, em.First(found, "", nil))
+	assert.Equal(t, "Sergeant", found.Rank)
+	assert.Equal(t, "retired", found.Status)
 
 	tx, err := em.TxBegin()
 	assert.Nil(t, err)
-	assert.NotNil(t, tx)
+	promoted := &trackedSoldier{ID: ryan.ID}
+	assert.Nil(t, tx.First(promoted, "", nil))
+	promoted.Rank = "General"
+	_, err = tx.Update(promoted)
+	assert.Nil(t, err)
+	assert.Nil(t, tx.Rollback())
 
-	tx.Rollback()
+	result, err = em.Update(promoted)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(1), result.NumRecordsAffected)
+	assert.Nil(t, em.First(found, "", nil))
+	assert.Equal(t, "General", found.Rank)
 
-	err = em.Close()
+	reloaded := &trackedSoldier{ID: ryan.ID}
+	assert.Nil(t, em.First(reloaded, "", nil))
+	_, err = em.UpdateWhere(&trackedSoldier{}, map[string]interface{}{"Rank": "Captain"}, "trackedSoldier.Name = :name", map[string]interface{}{"name": "Ryan"})
+	assert.Nil(t, err)
+	reloaded.Status = "active"
+	_, err = em.Update(reloaded)
 	assert.Nil(t, err)
+	assert.Nil(t, em.First(found, "", nil))
+	assert.Equal(t, "General", found.Rank)
+	assert.Equal(t, "active", found.Status)
+
+	nickname := "old"
+	reloaded.Nickname = &nickname
+	_, err = em.Update(reloaded)
+	assert.Nil(t, err)
+	*reloaded.Nickname = "Private Ryan"
+	result, err = em.Update(reloaded)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(1), result.NumRecordsAffected)
+	assert.Nil(t, em.First(found, "", nil))
+	assert.Equal(t, "Private Ryan", *found.Nickname)
+
+	assert.Nil(t, em.DropTable(&trackedSoldier{}))
 }
 
-func Test_Goedb_Migrate(t *testing.T) {
+func Test_Goedb_Update_Version(t *testing.T) {
 	em, err := GetEntityManager(persistenceUnitItComplexTest)
 	assert.Nil(t, err)
 	assert.NotNil(t, em)
 
-	err = em.Migrate(&troop{}, true, true)
+	err = em.Migrate(&versionedSoldier{}, true, true)
 	assert.Nil(t, err)
 
-	err = em.Migrate(&soldier{}, true, true)
+	_, err = em.Insert(&versionedSoldier{Name: "Ryan", Rank: "Private"})
+	assert.Nil(t, err)
+
+	first := &versionedSoldier{}
+	assert.Nil(t, em.First(first, "versionedSoldier.Name = :name", map[string]interface{}{"name": "Ryan"}))
+	second := &versionedSoldier{}
+	assert.Nil(t, em.First(second, "versionedSoldier.Name = :name", map[string]interface{}{"name": "Ryan"}))
+
+	first.Rank = "Corporal"
+	result, err := em.Update(first)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(1), result.NumRecordsAffected)
+	assert.Equal(t, 1, first.Version)
+
+	second.Rank = "Sergeant"
+	_, err = em.Update(second)
+	assert.True(t, errors.Is(err, database.ErrStaleEntity))
+	assert.Equal(t, 0, second.Version)
+	_, err = em.UpdateFields(second, "Rank")
+	assert.Equal(t, database.ErrStaleEntity, err)
+
+	first.Rank = "Sergeant"
+	_, err = em.UpdateFields(first, "Rank")
+	assert.Nil(t, err)
+	assert.Equal(t, 2, first.Version)
+
+	result, err = em.UpdateWhere(&versionedSoldier{}, map[string]interface{}{"Rank": "Captain"}, "versionedSoldier.Name = :name", map[string]interface{}{"name": "Ryan"})
 	assert.Nil(t, err)
+	assert.Equal(t, int64(1), result.NumRecordsAffected)
+	_, err = em.Update(first)
+	assert.Equal(t, database.ErrStaleEntity, err)
+
+	found := &versionedSoldier{ID: first.ID}
+	assert.Nil(t, em.First(found, "", nil))
+	assert.Equal(t, "Captain", found.Rank)
+	assert.Equal(t, 3, found.Version)
+
+	assert.Nil(t, em.DropTable(&versionedSoldier{}))
 }
 
-func Test_Goedb_Migrate_Recreate(t *testing.T) {
+func Test_Goedb_Upsert_Version(t *testing.T) {
 	em, err := GetEntityManager(persistenceUnitItComplexTest)
 	assert.Nil(t, err)
 	assert.NotNil(t, em)
 
-	err = em.Migrate(&troop{}, true, false)
+	err = em.Migrate(&versionedSoldier{}, true, true)
+	assert.Nil(t, err)
+
+	_, err = em.Upsert(&versionedSoldier{Name: "Ryan", Rank: "Private"}, "Name")
 	assert.NotNil(t, err)
+	found := &versionedSoldier{}
+	assert.NotNil(t, em.First(found, "versionedSoldier.Name = :name", map[string]interface{}{"name": "Ryan"}))
 
-	err = em.Migrate(&soldier{}, true, false)
+	_, err = em.Insert(&versionedSoldier{Name: "Ryan", Rank: "Private"})
+	assert.Nil(t, err)
+	stale := &versionedSoldier{Name: "Ryan", Rank: "Sergeant"}
+	_, err = em.Upsert(stale, "Name")
 	assert.NotNil(t, err)
+
+	assert.Nil(t, em.First(found, "versionedSoldier.Name = :name", map[string]interface{}{"name": "Ryan"}))
+	assert.Equal(t, "Private", found.Rank)
+	assert.Equal(t, 0, found.Version)
+
+	assert.Nil(t, em.DropTable(&versionedSoldier{}))
 }
 
-func Test_Goedb_Model(t *testing.T) {
+func Test_Goedb_Timestamps(t *testing.T) {
 	em, err := GetEntityManager(persistenceUnitItComplexTest)
 	assert.Nil(t, err)
 	assert.NotNil(t, em)
 
-	soldier1, err := em.Model(&soldier{})
+	now := time.Date(2017, 11, 5, 9, 30, 0, 0, time.UTC)
+	sqld := em.(*database.SQLDatabase)
+	sqld.Clock = func() time.Time { return now }
+	defer func() { sqld.Clock = nil }()
+
+	err = em.Migrate(&stampedSoldier{}, true, true)
 	assert.Nil(t, err)
-	assert.Equal(t, "soldier", soldier1.Name)
 
-	troop1, err := em.Model(&troop{})
+	ryan := &stampedSoldier{Name: "Ryan", Rank: "Private"}
+	_, err = em.Insert(ryan)
 	assert.Nil(t, err)
-	assert.Equal(t, "troop", troop1.Name)
+	assert.Equal(t, now, ryan.CreatedAt)
+	assert.Equal(t, now, *ryan.UpdatedAt)
+
+	created := now
+	now = now.Add(time.Hour)
+	ryan.Rank = "Corporal"
+	ryan.CreatedAt = time.Time{}
+	_, err = em.Update(ryan)
+	assert.Nil(t, err)
+
+	found := &stampedSoldier{ID: ryan.ID}
+	assert.Nil(t, em.First(found, "", nil))
+	assert.Equal(t, created, found.CreatedAt)
+	assert.Equal(t, now, *found.UpdatedAt)
+
+	now = now.Add(time.Hour)
+	found.Rank = "Sergeant"
+	_, err = em.UpdateFields(found, "Rank")
+	assert.Nil(t, err)
+	assert.Equal(t, now, *found.UpdatedAt)
+
+	now = now.Add(time.Hour)
+	_, err = em.UpdateWhere(&stampedSoldier{}, map[string]interface{}{"Rank": "Captain"}, "stampedSoldier.Name = :name", map[string]interface{}{"name": "Ryan"})
+	assert.Nil(t, err)
+
+	miller := stampedSoldier{Name: "Miller", Rank: "Captain", CreatedAt: created}
+	_, err = em.InsertAll([]*stampedSoldier{&miller})
+	assert.Nil(t, err)
+	assert.Equal(t, created, miller.CreatedAt)
+	assert.Equal(t, now, *miller.UpdatedAt)
+
+	soldiers := make([]stampedSoldier, 0)
+	assert.Nil(t, em.Find(&soldiers, "stampedSoldier.Rank = :rank", map[string]interface{}{"rank": "Captain"}))
+	assert.Equal(t, 2, len(soldiers))
+	for _, soldier := range soldiers {
+		assert.Equal(t, created, soldier.CreatedAt)
+		assert.Equal(t, now, *soldier.UpdatedAt)
+	}
+
+	stamped := now
+	now = now.Add(time.Hour)
+	miller.Name = "Ryan"
+	_, err = em.Update(&miller)
+	assert.NotNil(t, err)
+	assert.Equal(t, stamped, *miller.UpdatedAt)
+	_, err = em.UpdateFields(&miller, "Name")
+	assert.NotNil(t, err)
+	assert.Equal(t, stamped, *miller.UpdatedAt)
+
+	duplicated := &stampedSoldier{Name: "Ryan"}
+	_, err = em.Insert(duplicated)
+	assert.NotNil(t, err)
+	assert.True(t, duplicated.CreatedAt.IsZero())
+	assert.Nil(t, duplicated.UpdatedAt)
+
+	assert.Nil(t, em.DropTable(&stampedSoldier{}))
 }
 
-func Test_Goedb_Insert(t *testing.T) {
+func Test_Goedb_SoftDelete(t *testing.T) {
 	em, err := GetEntityManager(persistenceUnitItComplexTest)
 	assert.Nil(t, err)
 	assert.NotNil(t, em)
 
-	troop1 := &troop{
-		Name: "TheBestTeam",
-	}
+	now := time.Date(2017, 11, 5, 9, 30, 0, 0, time.UTC)
+	sqld := em.(*database.SQLDatabase)
+	sqld.Clock = func() time.Time { return now }
+	defer func() { sqld.Clock = nil }()
 
-	soldier1 := &soldier{
-		Name:  "Ryan",
-		Troop: *troop1,
-	}
+	assert.Nil(t, em.Migrate(&softTroop{}, true, true))
+	assert.Nil(t, em.Migrate(&softSoldier{}, true, true))
+
+	easy := &softTroop{Name: "Easy"}
+	_, err = em.Insert(easy)
+	assert.Nil(t, err)
+	ryan := &softSoldier{Name: "Ryan", Troop: *easy}
+	miller := &softSoldier{Name: "Miller", Troop: *easy}
+	_, err = em.InsertAll([]*softSoldier{ryan, miller})
+	assert.Nil(t, err)
 
-	_, err = em.Insert(troop1)
+	result, err := em.Remove(ryan, "", nil)
 	assert.Nil(t, err)
+	assert.Equal(t, int64(1), result.NumRecordsAffected)
+	assert.True(t, ryan.Removed)
 
-	soldier1.Troop.ID = 1
+	err = em.First(&softSoldier{ID: ryan.ID}, "", nil)
+	assert.NotNil(t, err)
+	soldiers := make([]softSoldier, 0)
+	assert.Nil(t, em.Find(&soldiers, "softSoldier.Name = :ryan OR softSoldier.Name = :miller", map[string]interface{}{"ryan": "Ryan", "miller": "Miller"}))
+	assert.Equal(t, 1, len(soldiers))
 
-	_, err = em.Insert(soldier1)
+	soldiers = make([]softSoldier, 0)
+	assert.Nil(t, em.Query(&soldiers).WithDeleted().Find())
+	assert.Equal(t, 2, len(soldiers))
+	found := &softSoldier{ID: ryan.ID}
+	assert.Nil(t, em.Unscoped().First(found, "", nil))
+	assert.True(t, found.Removed)
+
+	_, err = em.Restore(ryan)
 	assert.Nil(t, err)
+	assert.False(t, ryan.Removed)
+	assert.Nil(t, em.First(&softSoldier{ID: ryan.ID}, "", nil))
 
-	soldier1.Troop.ID = 2
-	_, err = em.Insert(soldier1)
+	result, err = em.RemoveWhere(&softTroop{}, "softTroop.Name = :name", map[string]interface{}{"name": "Easy"})
+	assert.Nil(t, err)
+	assert.Equal(t, int64(1), result.NumRecordsAffected)
+	err = em.Find(&[]softSoldier{}, "", nil)
+	assert.NotNil(t, err)
+	troop := &softTroop{ID: easy.ID}
+	assert.Nil(t, em.Unscoped().First(troop, "", nil))
+	assert.Equal(t, now, *troop.DeletedAt)
+
+	assert.Nil(t, em.Query(&soldiers).WithDeleted().Find())
+	_, err = em.Restore(troop)
+	assert.Nil(t, err)
+	assert.Nil(t, troop.DeletedAt)
+
+	result, err = em.Unscoped().Remove(miller, "", nil)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(1), result.NumRecordsAffected)
+	soldiers = make([]softSoldier, 0)
+	assert.Nil(t, em.Unscoped().Find(&soldiers, "", nil))
+	assert.Equal(t, 1, len(soldiers))
+
+	_, err = em.Restore(&upsertSoldier{})
+	assert.NotNil(t, err)
+
+	assert.Nil(t, em.DropTable(&softSoldier{}))
+	assert.Nil(t, em.DropTable(&softTroop{}))
+}
+
+func Test_Goedb_Hooks(t *testing.T) {
+	em, err := GetEntityManager(persistenceUnitItComplexTest)
+	assert.Nil(t, err)
+	assert.NotNil(t, em)
+
+	assert.Nil(t, em.Migrate(&hookedSoldier{}, true, true))
+	hookCalls = nil
+
+	ryan := &hookedSoldier{Name: "Ryan"}
+	_, err = em.Insert(ryan)
+	assert.Nil(t, err)
+	assert.Equal(t, "Private", ryan.Rank)
+	_, err = em.Insert(&hookedSoldier{})
+	assert.NotNil(t, err)
+	assert.Equal(t, []string{"BeforeInsert", "AfterInsert", "BeforeInsert"}, hookCalls)
+
+	soldiers := []hookedSoldier{{Name: "Miller", Rank: "Captain"}, {Name: "Horvath", Rank: "General"}}
+	_, err = em.InsertAll(soldiers)
+	assert.Nil(t, err)
+
+	found := make([]hookedSoldier, 0)
+	assert.Nil(t, em.Query(&found).OrderBy("hookedSoldier.ID").Find())
+	assert.Equal(t, []string{"Private Ryan", "Captain Miller", "General Horvath"}, []string{found[0].Display, found[1].Display, found[2].Display})
+
+	hookCalls = nil
+	ryan.Rank = "Corporal"
+	_, err = em.Update(ryan)
+	assert.Nil(t, err)
+	ryan.Name = ""
+	_, err = em.UpdateFields(ryan, "Name")
+	assert.NotNil(t, err)
+	assert.Equal(t, []string{"BeforeUpdate", "AfterUpdate", "BeforeUpdate"}, hookCalls)
+	first := &hookedSoldier{ID: ryan.ID}
+	assert.Nil(t, em.First(first, "", nil))
+	assert.Equal(t, "Corporal Ryan", first.Display)
+
+	hookCalls = nil
+	_, err = em.Remove(&found[2], "", nil)
+	assert.NotNil(t, err)
+	_, err = em.Remove(&found[1], "", nil)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"BeforeRemove", "BeforeRemove", "AfterRemove"}, hookCalls)
+
+	tx, err := em.TxBegin()
+	assert.Nil(t, err)
+	_, err = tx.Insert(&hookedSoldier{Name: "Mellish"})
+	assert.Nil(t, err)
+	_, err = tx.Insert(&hookedSoldier{})
 	assert.NotNil(t, err)
+	assert.NotNil(t, tx.Commit())
+	assert.NotNil(t, em.First(&hookedSoldier{}, "hookedSoldier.Name = :name", map[string]interface{}{"name": "Mellish"}))
+
+	assert.Nil(t, em.DropTable(&hookedSoldier{}))
 }
 
 func Test_Goedb_First_By_PrimaryKey(t *testing.T) {
@@ -243,6 +1566,62 @@ func Test_Find_One_Soldier(t *testing.T) {
 	assert.Equal(t, "Steve", foundSoldiers[0].Name)
 }
 
+func Test_Find_Soldiers_Context(t *testing.T) {
+	em, err := GetEntityManager(persistenceUnitItComplexTest)
+	assert.Nil(t, err)
+	assert.NotNil(t, em)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	foundSoldiers := make([]soldier, 0)
+	err = em.FindContext(ctx, &foundSoldiers, "", nil)
+	assert.Equal(t, context.Canceled, err)
+
+	soldier1 := &soldier{ID: 1}
+	err = em.FirstContext(ctx, soldier1, "", nil)
+	assert.Equal(t, context.Canceled, err)
+
+	ctx, cancel = context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+	err = em.FindContext(ctx, &foundSoldiers, "", nil)
+	assert.Nil(t, err)
+	assert.Equal(t, 5, len(foundSoldiers))
+}
+
+func Test_Query_Soldiers(t *testing.T) {
+	em, err := GetEntityManager(persistenceUnitItComplexTest)
+	assert.Nil(t, err)
+	assert.NotNil(t, em)
+
+	foundSoldiers := make([]soldier, 0)
+	err = em.Query(&foundSoldiers).OrderBy("soldier.Name ASC").Limit(2).Offset(1).Find()
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(foundSoldiers))
+	assert.Equal(t, "Chuck", foundSoldiers[0].Name)
+	assert.Equal(t, "Eduard", foundSoldiers[1].Name)
+	assert.Equal(t, 1, foundSoldiers[0].Troop.ID)
+
+	foundSoldiers = make([]soldier, 0)
+	err = em.Query(&foundSoldiers).
+		Where("soldier.ID > :id", map[string]interface{}{"id": 1}).
+		Where("soldier.Name <> :name", map[string]interface{}{"name": "Steve"}).
+		OrderBy("soldier.ID DESC").
+		Find()
+	assert.Nil(t, err)
+	assert.Equal(t, 3, len(foundSoldiers))
+	assert.Equal(t, "Chuck", foundSoldiers[0].Name)
+
+	soldier1 := &soldier{}
+	err = em.Query(soldier1).OrderBy("soldier.Name DESC").First()
+	assert.Nil(t, err)
+	assert.Equal(t, "Steve", soldier1.Name)
+
+	sql, args, err := em.Query(soldier1).Where("soldier.Name = :name", map[string]interface{}{"name": "Ryan"}).Limit(1).ToSQL()
+	assert.Nil(t, err)
+	assert.Equal(t, `SELECT "soldier"."ID","soldier"."Name","troop"."ID","troop"."Name" FROM "soldier","troop" WHERE soldier.Name = ? AND "soldier"."Troop" = "troop"."ID" LIMIT 1`, sql)
+	assert.Equal(t, []interface{}{"Ryan"}, args)
+}
+
 func Test_Native_Find_One_Soldier(t *testing.T) {
 	em, err := GetEntityManager(persistenceUnitItComplexTest)
 	assert.Nil(t, err)
@@ -304,6 +1683,87 @@ func Test_Delete_Soldier_By_OtherField(t *testing.T) {
 	assert.Equal(t, int64(1), result.NumRecordsAffected)
 }
 
+func Test_Transaction_Commit(t *testing.T) {
+	em, err := GetEntityManager(persistenceUnitItComplexTest)
+	assert.Nil(t, err)
+	assert.NotNil(t, em)
+
+	tx, err := em.TxBegin()
+	assert.Nil(t, err)
+
+	soldier1 := &soldier{Name: "Private", Troop: troop{ID: 1}}
+	_, err = tx.Insert(soldier1)
+	assert.Nil(t, err)
+
+	found := &soldier{ID: soldier1.ID}
+	err = tx.First(found, "", nil)
+	assert.Nil(t, err)
+	assert.Equal(t, "Private", found.Name)
+
+	err = tx.Commit()
+	assert.Nil(t, err)
+
+	found = &soldier{ID: soldier1.ID}
+	err = em.First(found, "", nil)
+	assert.Nil(t, err)
+
+	result, err := em.Remove(found, "", nil)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(1), result.NumRecordsAffected)
+}
+
+func Test_Transaction_Rollback(t *testing.T) {
+	em, err := GetEntityManager(persistenceUnitItComplexTest)
+	assert.Nil(t, err)
+	assert.NotNil(t, em)
+
+	soldier1 := &soldier{Name: "Deserter", Troop: troop{ID: 1}}
+	errAbort := errors.New("abort")
+	err = em.Transaction(func(tx database.EntityManager) error {
+		_, err := tx.Insert(soldier1)
+		assert.Nil(t, err)
+		return errAbort
+	})
+	assert.Equal(t, errAbort, err)
+
+	err = em.First(&soldier{}, "soldier.Name = :name", map[string]interface{}{"name": "Deserter"})
+	assert.NotNil(t, err)
+
+	assert.Panics(t, func() {
+		em.Transaction(func(tx database.EntityManager) error {
+			tx.Insert(soldier1)
+			panic("abort")
+		})
+	})
+
+	err = em.First(&soldier{}, "soldier.Name = :name", map[string]interface{}{"name": "Deserter"})
+	assert.NotNil(t, err)
+}
+
+func Test_Transaction_Nested(t *testing.T) {
+	em, err := GetEntityManager(persistenceUnitItComplexTest)
+	assert.Nil(t, err)
+	assert.NotNil(t, em)
+
+	err = em.Commit()
+	assert.NotNil(t, err)
+
+	err = em.Transaction(func(tx database.EntityManager) error {
+		_, err := tx.TxBegin()
+		assert.NotNil(t, err)
+
+		return tx.Transaction(func(inner database.EntityManager) error {
+			_, err := inner.Insert(&soldier{Name: "Sergeant", Troop: troop{ID: 1}})
+			return err
+		})
+	})
+	assert.Nil(t, err)
+
+	result, err := em.Remove(&soldier{}, "soldier.Name = :name", map[string]interface{}{"name": "Sergeant"})
+	assert.Nil(t, err)
+	assert.Equal(t, int64(1), result.NumRecordsAffected)
+}
+
 func Test_DropTable(t *testing.T) {
 	em, err := GetEntityManager(persistenceUnitItComplexTest)
 	assert.Nil(t, err)
@@ -0,0 +1,67 @@
+package goedb
+
+import (
+	"testing"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/plopezm/goedb/database/migrations"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Migrations_Up_Down(t *testing.T) {
+	migrations.Register(migrations.Migration{
+		Version: 3,
+		Name:    "insert_flag_go",
+		Up: func(tx *sqlx.Tx) error {
+			_, err := tx.Exec("INSERT INTO flag (ID, Name) VALUES (2, 'Overlord')")
+			return err
+		},
+		Down: func(tx *sqlx.Tx) error {
+			_, err := tx.Exec("DELETE FROM flag WHERE ID = 2")
+			return err
+		},
+	})
+
+	em, err := GetEntityManager(persistenceUnitItTest)
+	assert.Nil(t, err)
+
+	err = MigrateUp(persistenceUnitItTest)
+	assert.Nil(t, err)
+	err = MigrateUp(persistenceUnitItTest)
+	assert.Nil(t, err)
+
+	var flags int
+	err = em.GetDBConnection().Get(&flags, "SELECT COUNT(*) FROM flag")
+	assert.Nil(t, err)
+	assert.Equal(t, 2, flags)
+
+	status, err := MigrationStatus(persistenceUnitItTest)
+	assert.Nil(t, err)
+	assert.Equal(t, 3, len(status))
+	for _, migration := range status {
+		assert.True(t, migration.Applied)
+		assert.False(t, migration.AppliedAt.IsZero())
+	}
+
+	err = MigrateDown(persistenceUnitItTest, 2)
+	assert.Nil(t, err)
+
+	status, err = MigrationStatus(persistenceUnitItTest)
+	assert.Nil(t, err)
+	assert.True(t, status[0].Applied)
+	assert.False(t, status[1].Applied)
+	assert.False(t, status[2].Applied)
+
+	err = em.GetDBConnection().Get(&flags, "SELECT COUNT(*) FROM flag")
+	assert.Nil(t, err)
+	assert.Equal(t, 0, flags)
+
+	err = MigrateDown(persistenceUnitItTest, 5)
+	assert.Nil(t, err)
+
+	_, err = em.GetDBConnection().Exec("SELECT * FROM flag")
+	assert.NotNil(t, err)
+
+	err = MigrateUp("Not-exists")
+	assert.NotNil(t, err)
+}
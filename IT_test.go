@@ -325,6 +325,32 @@ func TestDB_Find_Not_Found(t *testing.T) {
 	}
 }
 
+func TestDB_Insert_Quoted_Values(t *testing.T) {
+	em, err := GetEntityManager(persistenceUnitItTest)
+	assert.Nil(t, err)
+	assert.NotNil(t, em)
+
+	newUser := &testuser{
+		Email:    "O'Brien",
+		Password: "'; DROP TABLE testuser; --",
+		DNI:      4321,
+	}
+
+	_, err = em.Insert(newUser)
+	assert.Nil(t, err)
+
+	foundUser := &testuser{
+		Email: "O'Brien",
+	}
+	err = em.First(foundUser, "", nil)
+	assert.Nil(t, err)
+	assert.Equal(t, "'; DROP TABLE testuser; --", foundUser.Password)
+
+	rs, err := em.Remove(foundUser, "", nil)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(1), rs.NumRecordsAffected)
+}
+
 func TestDB_Remove(t *testing.T) {
 	em, err := GetEntityManager(persistenceUnitItTest)
 	assert.Nil(t, err)
@@ -0,0 +1,322 @@
+// Package cli implements the goedb command line tool. The tool provided in cmd/goedb has no models registered, its ddl
+// command prints the tables found in the database. A project can build its own binary passing its structs to Run to
+// print the tables of its models
+package cli
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/plopezm/goedb"
+	"github.com/plopezm/goedb/config"
+	"github.com/plopezm/goedb/database"
+	"github.com/plopezm/goedb/database/dbaccess"
+	"github.com/plopezm/goedb/database/models"
+)
+
+const usage = `Usage: goedb [-config persistence.json] <command> [arguments]
+
+Commands:
+  migrate up -unit <name>              applies the pending migrations
+  migrate down -unit <name> [-steps n] reverts the last n migrations applied (1 by default)
+  migrate status -unit <name>          shows the state of each migration
+  ddl -unit <name>                     prints the CREATE TABLE sentences of the models (or of the database tables)
+  tables -unit <name>                  lists the tables and columns of the database
+  ping                                 checks the connection of every datasource
+`
+
+// errUsage is returned when the arguments are not valid, usage is printed and the exit code is 2
+var errUsage = errors.New("invalid arguments")
+
+type command struct {
+	config   string
+	stdout   io.Writer
+	entities []interface{}
+}
+
+// Run executes the command line arguments (without the program name) and returns the exit code.
+// The entities are the structs used by the ddl command
+func Run(args []string, stdout io.Writer, stderr io.Writer, entities ...interface{}) int {
+	cmd := &command{stdout: stdout, entities: entities}
+
+	flags := flag.NewFlagSet("goedb", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	flags.StringVar(&cmd.config, "config", "persistence.json", "persistence file with the datasources")
+	flags.Usage = func() { fmt.Fprint(stderr, usage) }
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+
+	err := cmd.run(flags.Args())
+	if err == errUsage {
+		fmt.Fprint(stderr, usage)
+		return 2
+	}
+	if err != nil {
+		fmt.Fprintln(stderr, "goedb:", err)
+		return 1
+	}
+	return 0
+}
+
+func (cmd *command) run(args []string) error {
+	if len(args) == 0 {
+		return errUsage
+	}
+	switch args[0] {
+	case "migrate":
+		if len(args) < 2 {
+			return errUsage
+		}
+		return cmd.migrate(args[1], args[2:])
+	case "ddl":
+		return cmd.ddl(args[1:])
+	case "tables":
+		return cmd.tables(args[1:])
+	case "ping":
+		return cmd.ping()
+	default:
+		return errUsage
+	}
+}
+
+// parseUnit parses the arguments of a command, unit is required
+func parseUnit(args []string, steps *int) (string, error) {
+	flags := flag.NewFlagSet("goedb", flag.ContinueOnError)
+	flags.SetOutput(ioutil.Discard)
+	unit := flags.String("unit", "", "persistence unit")
+	if steps != nil {
+		flags.IntVar(steps, "steps", 1, "number of migrations to revert")
+	}
+	if err := flags.Parse(args); err != nil || *unit == "" || flags.NArg() > 0 {
+		return "", errUsage
+	}
+	return *unit, nil
+}
+
+func (cmd *command) migrate(action string, args []string) error {
+	var steps int
+	var unit string
+	var err error
+	if action == "down" {
+		unit, err = parseUnit(args, &steps)
+	} else {
+		unit, err = parseUnit(args, nil)
+	}
+	if err != nil {
+		return err
+	}
+
+	if err := cmd.initialize(unit); err != nil {
+		return err
+	}
+	switch action {
+	case "up":
+		return goedb.MigrateUp(unit)
+	case "down":
+		return goedb.MigrateDown(unit, steps)
+	case "status":
+		status, err := goedb.MigrationStatus(unit)
+		if err != nil {
+			return err
+		}
+		writer := tabwriter.NewWriter(cmd.stdout, 0, 4, 2, ' ', 0)
+		fmt.Fprintln(writer, "VERSION\tNAME\tAPPLIED AT")
+		for _, migration := range status {
+			appliedAt := "pending"
+			if migration.Applied {
+				appliedAt = migration.AppliedAt.Format(time.RFC3339)
+			}
+			fmt.Fprintf(writer, "%d\t%s\t%s\n", migration.Version, migration.Name, appliedAt)
+		}
+		return writer.Flush()
+	default:
+		return errUsage
+	}
+}
+
+// ddl prints the CREATE TABLE sentences of the entities passed to Run, or of the tables found in the database when
+// there are no entities
+func (cmd *command) ddl(args []string) error {
+	unit, err := parseUnit(args, nil)
+	if err != nil {
+		return err
+	}
+	if len(cmd.entities) == 0 {
+		return cmd.schemaDDL(unit)
+	}
+	datasource, err := cmd.datasource(unit)
+	if err != nil {
+		return err
+	}
+	naming, err := models.GetNamingStrategy(datasource.Naming, datasource.TablePrefix)
+	if err != nil {
+		return err
+	}
+	databaseAccess, err := dbaccess.GetDatabaseAccess(datasource.Driver)
+	if err != nil {
+		return err
+	}
+	for _, entity := range cmd.entities {
+		table, err := models.ParseModelWithNaming(entity, naming)
+		if err != nil {
+			return err
+		}
+		databaseAccess.SetModel(models.ModelKey(models.GetType(entity)), table)
+		create, err := databaseAccess.Create(table)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintln(cmd.stdout, create+";")
+		for _, index := range databaseAccess.Indexes(table, models.TableSchema{}) {
+			fmt.Fprintln(cmd.stdout, index+";")
+		}
+	}
+	return nil
+}
+
+// schemaDDL prints the CREATE TABLE sentences of the tables found in the database. The schema only has the types,
+// defaults, primary keys and unique constraints of the columns, so NOT NULL, the foreign keys and the indexes are not printed
+func (cmd *command) schemaDDL(unit string) error {
+	if err := cmd.initialize(unit); err != nil {
+		return err
+	}
+	em, err := goedb.GetEntityManager(unit)
+	if err != nil {
+		return err
+	}
+	datasource, err := cmd.datasource(unit)
+	if err != nil {
+		return err
+	}
+	databaseAccess, err := dbaccess.GetDatabaseAccess(datasource.Driver)
+	if err != nil {
+		return err
+	}
+	tables, err := em.Tables()
+	if err != nil {
+		return err
+	}
+	for _, schema := range tables {
+		create, err := databaseAccess.Create(schemaTable(schema))
+		if err != nil {
+			return err
+		}
+		fmt.Fprintln(cmd.stdout, create+";")
+	}
+	return nil
+}
+
+// schemaTable returns the table whose columns are the ones found in the database, with their SQL types
+func schemaTable(schema models.TableSchema) models.Table {
+	table := models.Table{Name: schema.Name}
+	for _, column := range schema.Columns {
+		table.Columns = append(table.Columns, models.Column{
+			Title:      column.Name,
+			SQLType:    column.Type,
+			Default:    column.Default,
+			PrimaryKey: containsFold(schema.PrimaryKeys, column.Name),
+			Unique:     containsFold(schema.Uniques, column.Name) && !containsFold(schema.PrimaryKeys, column.Name),
+		})
+	}
+	return table
+}
+
+func containsFold(names []string, name string) bool {
+	for _, value := range names {
+		if strings.EqualFold(value, name) {
+			return true
+		}
+	}
+	return false
+}
+
+func (cmd *command) tables(args []string) error {
+	unit, err := parseUnit(args, nil)
+	if err != nil {
+		return err
+	}
+	if err := cmd.initialize(unit); err != nil {
+		return err
+	}
+	em, err := goedb.GetEntityManager(unit)
+	if err != nil {
+		return err
+	}
+	tables, err := em.Tables()
+	if err != nil {
+		return err
+	}
+	for _, table := range tables {
+		fmt.Fprintln(cmd.stdout, table.Name)
+		for _, column := range table.Columns {
+			fmt.Fprintln(cmd.stdout, "    "+describeColumn(table, column))
+		}
+	}
+	return nil
+}
+
+func describeColumn(table models.TableSchema, column models.ColumnSchema) string {
+	description := []string{column.Name, column.Type}
+	for _, constraint := range []struct {
+		name    string
+		columns []string
+	}{{"PRIMARY KEY", table.PrimaryKeys}, {"UNIQUE", table.Uniques}, {"FOREIGN KEY", table.ForeignKeys}} {
+		if containsFold(constraint.columns, column.Name) {
+			description = append(description, constraint.name)
+		}
+	}
+	if column.Default != "" {
+		description = append(description, "DEFAULT "+column.Default)
+	}
+	return strings.Join(description, " ")
+}
+
+// ping opens a connection with each datasource, an error is returned if any of them fails
+func (cmd *command) ping() error {
+	persistence := config.GetPersistenceConfig(cmd.config)
+	if len(persistence.Datasources) == 0 {
+		return errors.New("No datasources found in " + cmd.config)
+	}
+	failed := 0
+	for _, datasource := range persistence.Datasources {
+		db := new(database.SQLDatabase)
+		if err := db.Open(datasource.Driver, datasource.URL, datasource.Schema); err != nil {
+			fmt.Fprintf(cmd.stdout, "%s\tERROR\t%v\n", datasource.Name, err)
+			failed++
+			continue
+		}
+		db.Close()
+		fmt.Fprintf(cmd.stdout, "%s\tOK\n", datasource.Name)
+	}
+	if failed > 0 {
+		return errors.New(strconv.Itoa(failed) + " datasources failed")
+	}
+	return nil
+}
+
+// initialize reads the datasources of the config file, the errors of the other datasources are ignored when the
+// one of the unit is initialized
+func (cmd *command) initialize(unit string) error {
+	err := goedb.InitializeFile(cmd.config)
+	if _, found := goedb.GetEntityManager(unit); found == nil {
+		return nil
+	}
+	return err
+}
+
+func (cmd *command) datasource(unit string) (config.Datasource, error) {
+	for _, datasource := range config.GetPersistenceConfig(cmd.config).Datasources {
+		if datasource.Name == unit {
+			return datasource, nil
+		}
+	}
+	return config.Datasource{}, errors.New("Persistence unit \"" + unit + "\" not found in " + cmd.config)
+}
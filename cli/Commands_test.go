@@ -0,0 +1,114 @@
+package cli
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/stretchr/testify/assert"
+)
+
+type clitroop struct {
+	ID   int    `goedb:"pk,autoincrement"`
+	Name string `goedb:"unique,index"`
+}
+
+func writePersistence(t *testing.T) (string, func()) {
+	dir, err := ioutil.TempDir("", "goedb-cli")
+	assert.Nil(t, err)
+	migrations, err := filepath.Abs("../testdata/migrations")
+	assert.Nil(t, err)
+	persistence := `{"datasources":[{"name":"cli","driver":"sqlite3","url":"` + filepath.Join(dir, "cli.db") + `","migrations":"` + migrations + `"}]}`
+	file := filepath.Join(dir, "persistence.json")
+	assert.Nil(t, ioutil.WriteFile(file, []byte(persistence), 0644))
+	return file, func() { os.RemoveAll(dir) }
+}
+
+func run(args ...string) (int, string, string) {
+	var stdout, stderr bytes.Buffer
+	code := Run(args, &stdout, &stderr, &clitroop{})
+	return code, stdout.String(), stderr.String()
+}
+
+func TestRun_Usage(t *testing.T) {
+	code, _, stderr := run()
+	assert.Equal(t, 2, code)
+	assert.True(t, strings.HasPrefix(stderr, "Usage: goedb"))
+	assert.Contains(t, stderr, "ddl -unit")
+
+	code, _, _ = run("migrate", "sideways", "-unit", "cli")
+	assert.Equal(t, 2, code)
+
+	code, _, _ = run("tables")
+	assert.Equal(t, 2, code)
+}
+
+func TestRun_Ping(t *testing.T) {
+	file, remove := writePersistence(t)
+	defer remove()
+
+	code, stdout, _ := run("-config", file, "ping")
+	assert.Equal(t, 0, code)
+	assert.Equal(t, "cli\tOK\n", stdout)
+
+	code, _, stderr := run("-config", "notfound.json", "ping")
+	assert.Equal(t, 1, code)
+	assert.Contains(t, stderr, "No datasources found")
+}
+
+func TestRun_DDL(t *testing.T) {
+	file, remove := writePersistence(t)
+	defer remove()
+
+	code, stdout, _ := run("-config", file, "ddl", "-unit", "cli")
+	assert.Equal(t, 0, code)
+	assert.Equal(t, `CREATE TABLE "clitroop" ("ID" INTEGER PRIMARY KEY AUTOINCREMENT,"Name" VARCHAR UNIQUE);`+"\n"+
+		`CREATE INDEX "idx_clitroop_Name" ON "clitroop" ("Name");`+"\n", stdout)
+}
+
+func TestRun_DDL_Schema(t *testing.T) {
+	file, remove := writePersistence(t)
+	defer remove()
+
+	code, _, stderr := run("-config", file, "migrate", "up", "-unit", "cli")
+	assert.Equal(t, 0, code, stderr)
+
+	var stdout bytes.Buffer
+	code = Run([]string{"-config", file, "ddl", "-unit", "cli"}, &stdout, &bytes.Buffer{})
+	assert.Equal(t, 0, code)
+	assert.Contains(t, stdout.String(), `CREATE TABLE "flag" ("ID" INTEGER,"Name" VARCHAR UNIQUE, PRIMARY KEY ("ID"));`+"\n")
+}
+
+func TestRun_Migrate_And_Tables(t *testing.T) {
+	file, remove := writePersistence(t)
+	defer remove()
+
+	code, _, stderr := run("-config", file, "migrate", "up", "-unit", "cli")
+	assert.Equal(t, 0, code, stderr)
+
+	code, stdout, _ := run("-config", file, "migrate", "status", "-unit", "cli")
+	assert.Equal(t, 0, code)
+	lines := strings.Split(strings.TrimSpace(stdout), "\n")
+	assert.Equal(t, 3, len(lines))
+	assert.True(t, strings.HasPrefix(lines[1], "1  "))
+	assert.NotContains(t, stdout, "pending")
+
+	code, stdout, _ = run("-config", file, "tables", "-unit", "cli")
+	assert.Equal(t, 0, code)
+	assert.Contains(t, stdout, "flag\n    ID INTEGER PRIMARY KEY\n    Name VARCHAR UNIQUE\n")
+
+	code, _, _ = run("-config", file, "migrate", "down", "-unit", "cli", "-steps", "1")
+	assert.Equal(t, 0, code)
+
+	code, stdout, _ = run("-config", file, "migrate", "status", "-unit", "cli")
+	assert.Equal(t, 0, code)
+	assert.Contains(t, stdout, "pending")
+
+	code, _, stderr = run("-config", file, "tables", "-unit", "notfound")
+	assert.Equal(t, 1, code)
+	assert.Contains(t, stderr, "not found")
+}
@@ -0,0 +1,15 @@
+// Command goedb runs the versioned migrations and inspects the datasources defined in persistence.json
+package main
+
+import (
+	"os"
+
+	_ "github.com/go-sql-driver/mysql"
+	_ "github.com/lib/pq"
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/plopezm/goedb/cli"
+)
+
+func main() {
+	os.Exit(cli.Run(os.Args[1:], os.Stdout, os.Stderr))
+}
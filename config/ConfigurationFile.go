@@ -3,6 +3,7 @@ package config
 import (
 	"encoding/json"
 	"io/ioutil"
+	"time"
 )
 
 // Persistence represents the collection of datasources defined by the developer in persistence.json
@@ -16,6 +17,24 @@ type Datasource struct {
 	Driver string `json:"driver"`
 	URL    string `json:"url"`
 	Schema string `json:"schema"`
+	// StatementTimeout is the default timeout of each statement, using time.Duration format (e.g. "500ms", "5s")
+	StatementTimeout string `json:"statementTimeout"`
+	// Migrations is the directory of the versioned migration files applied by goedb.MigrateUp
+	Migrations string `json:"migrations"`
+	// Naming is the strategy used to name the tables and columns of the models: "snake_case", "lowercase" or empty to use the Go names
+	Naming string `json:"naming"`
+	// TablePrefix is added to the table names generated by the naming strategy
+	TablePrefix string `json:"tablePrefix"`
+	// Snapshots makes the entity manager keep the values of the entities loaded that embed database.Tracked, so Update only writes the columns changed
+	Snapshots bool `json:"snapshots"`
+}
+
+// GetStatementTimeout returns the default statement timeout of the datasource, 0 if it is not defined
+func (datasource Datasource) GetStatementTimeout() (time.Duration, error) {
+	if datasource.StatementTimeout == "" {
+		return 0, nil
+	}
+	return time.ParseDuration(datasource.StatementTimeout)
 }
 
 // GetPersistenceConfig generates the persistence struct from persistence.json
@@ -2,6 +2,7 @@ package config
 
 import (
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -15,3 +16,24 @@ func TestGetPersistenceConfigNotFound(t *testing.T) {
 	persistence := GetPersistenceConfig("Notfoundfile.json")
 	assert.Equal(t, 0, len(persistence.Datasources))
 }
+
+func TestDatasource_GetStatementTimeout(t *testing.T) {
+	persistence := GetPersistenceConfig("persistence.json")
+
+	timeout, err := persistence.Datasources[0].GetStatementTimeout()
+	assert.Nil(t, err)
+	assert.Equal(t, 5*time.Second, timeout)
+
+	timeout, err = persistence.Datasources[1].GetStatementTimeout()
+	assert.Nil(t, err)
+	assert.Equal(t, time.Duration(0), timeout)
+
+	_, err = Datasource{StatementTimeout: "five seconds"}.GetStatementTimeout()
+	assert.NotNil(t, err)
+}
+
+func TestDatasource_Migrations(t *testing.T) {
+	persistence := GetPersistenceConfig("persistence.json")
+	assert.Equal(t, "./testdata/migrations", persistence.Datasources[0].Migrations)
+	assert.Equal(t, "", persistence.Datasources[1].Migrations)
+}
@@ -1,27 +1,67 @@
 package database
 
 import (
+	"context"
 	"database/sql"
+	"errors"
 
 	"github.com/jmoiron/sqlx"
 	"github.com/plopezm/goedb/database/models"
 )
 
-// EntityManager is the manager used to interact with the database
+// ErrStaleEntity is returned when an entity with a version column is updated but its row was changed
+// (or removed) after the entity was loaded, so its version is not the one stored anymore
+var ErrStaleEntity = errors.New("Stale entity, the row was changed or removed after the entity was loaded")
+
+// EntityManager is the manager used to interact with the database.
+// The methods ending with Context receive the context used to run the statements, if it
+// has no deadline the statement timeout of the datasource is applied.
+// The entity manager returned by TxBegin runs every operation inside the transaction until
+// Commit or Rollback are called
 type EntityManager interface {
 	SetSchema(schema string) (sql.Result, error)
 	Open(driver string, params string, schema string) error
 	Close() error
 	GetDBConnection() *sqlx.DB
 	Migrate(i interface{}, autoCreate bool, dropIfExists bool) error
+	MigrateContext(ctx context.Context, i interface{}, autoCreate bool, dropIfExists bool) error
 	DropTable(i interface{}) error
+	DropTableContext(ctx context.Context, i interface{}) error
+	Tables() ([]models.TableSchema, error)
+	TablesContext(ctx context.Context) ([]models.TableSchema, error)
 	Model(i interface{}) (models.Table, error)
 	Insert(i interface{}) (models.Result, error)
+	InsertContext(ctx context.Context, i interface{}) (models.Result, error)
+	InsertAll(slice interface{}) (models.Result, error)
+	InsertAllContext(ctx context.Context, slice interface{}) (models.Result, error)
+	Upsert(i interface{}, conflictColumns ...string) (models.Result, error)
+	UpsertContext(ctx context.Context, i interface{}, conflictColumns ...string) (models.Result, error)
 	Update(i interface{}) (models.Result, error)
+	UpdateContext(ctx context.Context, i interface{}) (models.Result, error)
+	UpdateFields(i interface{}, columns ...string) (models.Result, error)
+	UpdateFieldsContext(ctx context.Context, i interface{}, columns ...string) (models.Result, error)
+	UpdateWhere(i interface{}, values map[string]interface{}, where string, params map[string]interface{}) (models.Result, error)
+	UpdateWhereContext(ctx context.Context, i interface{}, values map[string]interface{}, where string, params map[string]interface{}) (models.Result, error)
 	Remove(i interface{}, where string, params map[string]interface{}) (models.Result, error)
+	RemoveContext(ctx context.Context, i interface{}, where string, params map[string]interface{}) (models.Result, error)
+	RemoveWhere(i interface{}, where string, params map[string]interface{}) (models.Result, error)
+	RemoveWhereContext(ctx context.Context, i interface{}, where string, params map[string]interface{}) (models.Result, error)
+	Restore(i interface{}) (models.Result, error)
+	RestoreContext(ctx context.Context, i interface{}) (models.Result, error)
+	Unscoped() EntityManager
 	First(i interface{}, where string, params map[string]interface{}) error
+	FirstContext(ctx context.Context, i interface{}, where string, params map[string]interface{}) error
 	Find(i interface{}, where string, params map[string]interface{}) error
+	FindContext(ctx context.Context, i interface{}, where string, params map[string]interface{}) error
 	NativeFirst(i interface{}, query string, params map[string]interface{}) error
+	NativeFirstContext(ctx context.Context, i interface{}, query string, params map[string]interface{}) error
 	NativeFind(i interface{}, query string, params map[string]interface{}) error
-	TxBegin() (*sql.Tx, error)
+	Query(i interface{}) *Query
+	NativeFindContext(ctx context.Context, i interface{}, query string, params map[string]interface{}) error
+	TxBegin() (EntityManager, error)
+	TxBeginContext(ctx context.Context, opts *sql.TxOptions) (EntityManager, error)
+	Commit() error
+	Rollback() error
+	Transaction(fn func(tx EntityManager) error) error
+	TransactionContext(ctx context.Context, opts *sql.TxOptions, fn func(tx EntityManager) error) error
 }
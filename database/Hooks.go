@@ -0,0 +1,104 @@
+package database
+
+// The entities can implement the hook interfaces to run code around their persistence, the hooks receive the entity
+// manager running the operation (the transaction-scoped one inside a transaction). An error returned by a Before hook
+// aborts the operation and an error returned by an After hook is returned by the operation, in both cases the
+// transaction of a transaction-scoped entity manager is rolled back
+
+// BeforeInserter is implemented by the entities that run code before being inserted by Insert, InsertAll or Upsert
+type BeforeInserter interface {
+	BeforeInsert(em EntityManager) error
+}
+
+// AfterInserter is implemented by the entities that run code after being inserted by Insert, InsertAll or Upsert
+type AfterInserter interface {
+	AfterInsert(em EntityManager) error
+}
+
+// BeforeUpdater is implemented by the entities that run code before being updated by Update or UpdateFields
+type BeforeUpdater interface {
+	BeforeUpdate(em EntityManager) error
+}
+
+// AfterUpdater is implemented by the entities that run code after being updated by Update or UpdateFields
+type AfterUpdater interface {
+	AfterUpdate(em EntityManager) error
+}
+
+// BeforeRemover is implemented by the entities that run code before being removed by Remove using their primary key
+type BeforeRemover interface {
+	BeforeRemove(em EntityManager) error
+}
+
+// AfterRemover is implemented by the entities that run code after being removed by Remove using their primary key
+type AfterRemover interface {
+	AfterRemove(em EntityManager) error
+}
+
+// AfterFinder is implemented by the entities that run code after being loaded by First, Find or a Query
+type AfterFinder interface {
+	AfterFind(em EntityManager) error
+}
+
+// runHook calls a hook if the entity implements it, the transaction is rolled back if the hook fails
+func (sqld *SQLDatabase) runHook(hook func(em EntityManager) error) error {
+	if hook == nil {
+		return nil
+	}
+	if err := hook(sqld); err != nil {
+		if sqld.tx != nil {
+			sqld.Rollback()
+		}
+		return err
+	}
+	return nil
+}
+
+func beforeInsert(instance interface{}) func(em EntityManager) error {
+	if hook, ok := instance.(BeforeInserter); ok {
+		return hook.BeforeInsert
+	}
+	return nil
+}
+
+func afterInsert(instance interface{}) func(em EntityManager) error {
+	if hook, ok := instance.(AfterInserter); ok {
+		return hook.AfterInsert
+	}
+	return nil
+}
+
+func beforeUpdate(instance interface{}) func(em EntityManager) error {
+	if hook, ok := instance.(BeforeUpdater); ok {
+		return hook.BeforeUpdate
+	}
+	return nil
+}
+
+func afterUpdate(instance interface{}) func(em EntityManager) error {
+	if hook, ok := instance.(AfterUpdater); ok {
+		return hook.AfterUpdate
+	}
+	return nil
+}
+
+func beforeRemove(instance interface{}) func(em EntityManager) error {
+	if hook, ok := instance.(BeforeRemover); ok {
+		return hook.BeforeRemove
+	}
+	return nil
+}
+
+func afterRemove(instance interface{}) func(em EntityManager) error {
+	if hook, ok := instance.(AfterRemover); ok {
+		return hook.AfterRemove
+	}
+	return nil
+}
+
+func afterFind(instance interface{}) func(em EntityManager) error {
+	if hook, ok := instance.(AfterFinder); ok {
+		return hook.AfterFind
+	}
+	return nil
+}
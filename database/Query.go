@@ -0,0 +1,163 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/plopezm/goedb/database/dbaccess"
+)
+
+// Query is a chainable builder of the SELECT sentences generated for an entity, for example:
+//
+//	em.Query(&[]soldier{}).Where("soldier.Name = :n", p).OrderBy("soldier.ID DESC").Limit(20).Offset(40).Find()
+type Query struct {
+	sqld       *SQLDatabase
+	instance   interface{}
+	query      dbaccess.Query
+	jsonParams int
+	err        error
+}
+
+var jsonOperators = map[string]bool{"=": true, "<>": true, "!=": true, "<": true, "<=": true, ">": true, ">=": true, "LIKE": true}
+
+// Query returns a query builder over the model of the instance, which can be a pointer to a
+// struct (First) or a pointer to a slice of structs (Find)
+func (sqld *SQLDatabase) Query(instance interface{}) *Query {
+	return &Query{sqld: sqld, instance: instance}
+}
+
+// Where adds a condition to the query, named parameters (:name) are taken from params.
+// Calling Where more than once joins the conditions using AND
+func (q *Query) Where(where string, params map[string]interface{}) *Query {
+	if q.query.Where == "" {
+		q.query.Where = where
+	} else {
+		q.query.Where = "(" + q.query.Where + ") AND (" + where + ")"
+	}
+	if q.query.Params == nil {
+		q.query.Params = make(map[string]interface{})
+	}
+	for key, value := range params {
+		q.query.Params[key] = value
+	}
+	return q
+}
+
+// WhereJSON adds a condition over a path of a json column, the elements of the path are separated by dots
+// and numeric elements are array indexes, for example:
+//
+//	em.Query(&[]soldier{}).WhereJSON("soldier.Settings", "address.city", "=", "Madrid").Find()
+func (q *Query) WhereJSON(column string, path string, operator string, value interface{}) *Query {
+	if !jsonOperators[strings.ToUpper(operator)] {
+		q.setError(errors.New("Operator " + operator + " not supported"))
+		return q
+	}
+	expression, err := q.sqld.DBAccess.JSONPath(column, strings.Split(path, "."), value)
+	if err != nil {
+		q.setError(err)
+		return q
+	}
+	q.jsonParams++
+	param := "goedb_json_" + strconv.Itoa(q.jsonParams)
+	return q.Where(expression+" "+operator+" :"+param, map[string]interface{}{param: value})
+}
+
+// setError keeps the first error found building the query, it is returned when the query is run
+func (q *Query) setError(err error) {
+	if q.err == nil {
+		q.err = err
+	}
+}
+
+// OrderBy sets the ORDER BY clause of the query, for example "soldier.Name ASC, soldier.ID DESC"
+func (q *Query) OrderBy(orderBy string) *Query {
+	q.query.OrderBy = orderBy
+	return q
+}
+
+// WithDeleted includes the rows soft deleted in the results
+func (q *Query) WithDeleted() *Query {
+	q.query.WithDeleted = true
+	return q
+}
+
+// Limit sets the maximum number of rows returned
+func (q *Query) Limit(limit int) *Query {
+	q.query.Limit = limit
+	return q
+}
+
+// Offset sets the number of rows skipped before returning rows
+func (q *Query) Offset(offset int) *Query {
+	q.query.Offset = offset
+	return q
+}
+
+// ToSQL returns the sentence, using the placeholders of the database, and the arguments that will be used to run the query
+func (q *Query) ToSQL() (string, []interface{}, error) {
+	if q.err != nil {
+		return "", nil, q.err
+	}
+	model, err := q.sqld.Model(q.instance)
+	if err != nil {
+		return "", nil, err
+	}
+	sql, args, err := q.sqld.DBAccess.Select(model, q.query)
+	if err != nil {
+		return "", nil, err
+	}
+	return q.sqld.DBAccess.Rebind(sql), args, nil
+}
+
+// Find appends every row found to the slice pointed by the instance of the query
+func (q *Query) Find() error {
+	return q.FindContext(context.Background())
+}
+
+// FindContext appends every row found to the slice pointed by the instance of the query
+func (q *Query) FindContext(ctx context.Context) error {
+	if q.err != nil {
+		return q.err
+	}
+	if reflect.TypeOf(q.instance).Elem().Kind() != reflect.Slice {
+		return errors.New("The intput value is not a pointer of a slice")
+	}
+	model, err := q.sqld.Model(q.instance)
+	if err != nil {
+		return err
+	}
+	sql, args, err := q.sqld.DBAccess.Select(model, q.query)
+	if err != nil {
+		return err
+	}
+	return q.sqld.queryAll(ctx, model, q.instance, sql, args)
+}
+
+// First scans the first row found into the instance of the query
+func (q *Query) First() error {
+	return q.FirstContext(context.Background())
+}
+
+// FirstContext scans the first row found into the instance of the query
+func (q *Query) FirstContext(ctx context.Context) error {
+	if q.err != nil {
+		return q.err
+	}
+	if reflect.TypeOf(q.instance).Elem().Kind() != reflect.Struct {
+		return errors.New("The intput value is not a pointer of a struct")
+	}
+	model, err := q.sqld.Model(q.instance)
+	if err != nil {
+		return err
+	}
+	query := q.query
+	query.Limit = 1
+	sql, args, err := q.sqld.DBAccess.Select(model, query)
+	if err != nil {
+		return err
+	}
+	return q.sqld.queryFirst(ctx, model, q.instance, sql, args)
+}
@@ -1,10 +1,12 @@
 package database
 
 import (
+	"context"
 	"database/sql"
 	"errors"
-	"fmt"
 	"reflect"
+	"strings"
+	"time"
 
 	"github.com/jmoiron/sqlx"
 	"github.com/plopezm/goedb/config"
@@ -15,8 +17,19 @@ import (
 //SQLDatabase is the implementation of SQL for a Database interface
 type SQLDatabase struct {
 	db         *sqlx.DB
+	tx         *sqlx.Tx
 	DBAccess   dbaccess.DatabaseAccess
 	Datasource config.Datasource
+	// StatementTimeout is applied to the statements whose context has no deadline, 0 means no timeout
+	StatementTimeout time.Duration
+	// Naming generates the names of the tables and columns of the models, the Go names are used if it is nil
+	Naming models.NamingStrategy
+	// Snapshots keeps the original values of the entities loaded, if it is nil Update writes every column
+	Snapshots *Snapshots
+	// Clock returns the time written into the createdAt, updatedAt and softDelete columns, time.Now is used if it is nil
+	Clock func() time.Time
+	// unscoped is true in the entity managers returned by Unscoped, they include the rows soft deleted and remove rows for good
+	unscoped bool
 }
 
 // SetSchema sets the schema as default schema for a datasource
@@ -53,12 +66,23 @@ func (sqld *SQLDatabase) Open(driver string, params string, schema string) error
 
 // Close finishes the connection
 func (sqld *SQLDatabase) Close() error {
+	if sqld.tx != nil {
+		return errors.New("Transaction must be finished using Commit or Rollback")
+	}
 	if sqld.db == nil {
 		return errors.New("DB is closed")
 	}
 	return sqld.db.Close()
 }
 
+// executor returns the transaction of a transaction-scoped entity manager, otherwise it returns the database
+func (sqld *SQLDatabase) executor() sqlx.ExtContext {
+	if sqld.tx != nil {
+		return sqld.tx
+	}
+	return sqld.db
+}
+
 // GetDBConnection returns the DB connection as *sqlx.DB.
 // This method can be used if you wanna perform some query manually
 func (sqld *SQLDatabase) GetDBConnection() *sqlx.DB {
@@ -68,115 +92,773 @@ func (sqld *SQLDatabase) GetDBConnection() *sqlx.DB {
 // Model returns the metadata of each structure migrated
 func (sqld *SQLDatabase) Model(i interface{}) (models.Table, error) {
 	var table models.Table
-	if table, ok := sqld.DBAccess.GetModel(models.GetType(i).Name()); ok {
+	if table, ok := sqld.DBAccess.GetModel(models.ModelKey(models.GetType(i))); ok {
 		return table, nil
 	}
 	return table, errors.New("Model not found")
 }
 
-// Migrate creates the table in the database
-func (sqld *SQLDatabase) Migrate(i interface{}, autoCreate bool, dropIfExists bool) (err error) {
+// withTimeout returns a context with the statement timeout of the datasource if the context received has no deadline
+func (sqld *SQLDatabase) withTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	if _, ok := ctx.Deadline(); ok || sqld.StatementTimeout <= 0 {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeout(ctx, sqld.StatementTimeout)
+}
+
+// Migrate creates the table in the database. If the table already exists, the new columns,
+// unique constraints, foreign keys and indexes of the model are added to it
+func (sqld *SQLDatabase) Migrate(i interface{}, autoCreate bool, dropIfExists bool) error {
+	return sqld.MigrateContext(context.Background(), i, autoCreate, dropIfExists)
+}
+
+// MigrateContext creates the table in the database. If the table already exists, the new columns,
+// unique constraints, foreign keys and indexes of the model are added to it
+func (sqld *SQLDatabase) MigrateContext(ctx context.Context, i interface{}, autoCreate bool, dropIfExists bool) (err error) {
+	naming := sqld.Naming
+	if naming == nil {
+		naming = models.DefaultNaming{}
+	}
+	table, err := models.ParseModelWithNaming(i, naming)
+	if err != nil {
+		return err
+	}
+	sqld.DBAccess.SetModel(models.ModelKey(models.GetType(i)), table)
+
+	ctx, cancel := sqld.withTimeout(ctx)
+	defer cancel()
 	if dropIfExists {
-		sqld.DropTable(i)
+		if _, err = sqld.executor().ExecContext(ctx, sqld.DBAccess.Drop(table.Name, true)); err != nil {
+			return err
+		}
 	}
-	table := models.ParseModel(i)
-	sqld.DBAccess.SetModel(table.Name, table)
-	if autoCreate {
-		sqltab := sqld.DBAccess.Create(table)
-		_, err = sqld.db.Exec(sqltab)
+	if !autoCreate {
+		return nil
 	}
-	return err
+	schema, err := sqld.DBAccess.Schema(ctx, sqld.executor(), table.Name)
+	if err != nil {
+		return err
+	}
+	if len(schema.Columns) == 0 {
+		create, err := sqld.DBAccess.Create(table)
+		if err != nil {
+			return err
+		}
+		sentences := append([]string{create}, sqld.DBAccess.Indexes(table, schema)...)
+		return sqld.execSentences(ctx, sentences)
+	}
+
+	migration, err := sqld.DBAccess.Alter(table, schema)
+	if err != nil {
+		return err
+	}
+	if migration.Rebuild {
+		return sqld.rebuildTable(ctx, migration)
+	}
+	return sqld.execSentences(ctx, migration.Sentences)
 }
 
-// Insert creates a new row with the object in the database (it must be migrated)
-func (sqld *SQLDatabase) Insert(instance interface{}) (goedbres models.Result, err error) {
+// execSentences runs the sentences in a transaction, or in the current one if the entity manager is transaction-scoped
+func (sqld *SQLDatabase) execSentences(ctx context.Context, sentences []string) error {
+	if len(sentences) == 0 {
+		return nil
+	}
+	return sqld.TransactionContext(ctx, nil, func(tx EntityManager) error {
+		for _, sentence := range sentences {
+			if _, err := tx.(*SQLDatabase).executor().ExecContext(ctx, sentence); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// rebuildTable runs the sentences of a rebuild migration in a dedicated connection with the foreign keys disabled,
+// so the rows referencing the table are not deleted when the old table is dropped
+func (sqld *SQLDatabase) rebuildTable(ctx context.Context, migration dbaccess.Migration) error {
+	if sqld.tx != nil {
+		return errors.New("The table must be rebuilt, it cannot be migrated inside a transaction")
+	}
+	conn, err := sqld.db.Conn(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	if _, err = conn.ExecContext(ctx, migration.ForeignKeysOff); err != nil {
+		return err
+	}
+	defer conn.ExecContext(context.Background(), migration.ForeignKeysOn)
+
+	tx, err := conn.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	for _, sentence := range migration.Sentences {
+		if _, err = tx.ExecContext(ctx, sentence); err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+// Insert creates a new row with the object in the database (it must be migrated).
+// The values generated by the database (autoincrement and default columns) are written back into the instance
+func (sqld *SQLDatabase) Insert(instance interface{}) (models.Result, error) {
+	return sqld.InsertContext(context.Background(), instance)
+}
+
+// InsertContext creates a new row with the object in the database (it must be migrated).
+// The values generated by the database (autoincrement and default columns) are written back into the instance
+func (sqld *SQLDatabase) InsertContext(ctx context.Context, instance interface{}) (goedbres models.Result, err error) {
 	var result sql.Result
 	model, err := sqld.Model(instance)
 	if err != nil {
 		return goedbres, err
 	}
+	if err = sqld.runHook(beforeInsert(instance)); err != nil {
+		return goedbres, err
+	}
+	defer func() {
+		if err == nil {
+			err = sqld.runHook(afterInsert(instance))
+		}
+	}()
 
-	sql, err := sqld.DBAccess.Insert(model, instance)
+	defer restoreOnError(&err, sqld.stamp(model, instance, true))
+	sql, args, returning, err := sqld.DBAccess.Insert(model, instance)
 	if err != nil {
 		return goedbres, err
 	}
-	result, err = sqld.db.Exec(sql)
+	generated := sqld.DBAccess.Generated(model, instance)
+
+	ctx, cancel := sqld.withTimeout(ctx)
+	defer cancel()
+
+	if returning {
+		err = sqld.executor().QueryRowxContext(ctx, sqld.DBAccess.Rebind(sql), args...).Scan(getFieldAddresses(instance, generated)...)
+		if err != nil {
+			return goedbres, err
+		}
+		goedbres.NumRecordsAffected = 1
+		goedbres.LastInsertId = getAutoIncrementValue(instance, generated)
+		return goedbres, nil
+	}
+
+	result, err = sqld.executor().ExecContext(ctx, sqld.DBAccess.Rebind(sql), args...)
+	if err != nil {
+		return goedbres, err
+	}
+
+	goedbres.NumRecordsAffected, _ = result.RowsAffected()
+	goedbres.LastInsertId, _ = result.LastInsertId()
+	if len(generated) == 0 {
+		return goedbres, nil
+	}
+	if len(generated) == 1 && generated[0].AutoIncrement {
+		setIntValue(models.GetValue(instance).FieldByName(generated[0].Field), goedbres.LastInsertId)
+		return goedbres, nil
+	}
+	sql, args, ok := sqld.DBAccess.InsertedRow(model, generated, instance, goedbres.LastInsertId)
+	if !ok {
+		return goedbres, nil
+	}
+	err = sqld.executor().QueryRowxContext(ctx, sqld.DBAccess.Rebind(sql), args...).Scan(getFieldAddresses(instance, generated)...)
+	return goedbres, err
+}
+
+// InsertAll inserts the structs of a slice (or of a pointer to a slice) using multi-row INSERT sentences run in a single
+// transaction. The values generated by the database are written back into the structs when the dialect can return them
+func (sqld *SQLDatabase) InsertAll(slice interface{}) (models.Result, error) {
+	return sqld.InsertAllContext(context.Background(), slice)
+}
+
+// InsertAllContext inserts the structs of a slice (or of a pointer to a slice) using multi-row INSERT sentences run in a single transaction
+func (sqld *SQLDatabase) InsertAllContext(ctx context.Context, slice interface{}) (goedbres models.Result, err error) {
+	sliceValue := models.GetValue(slice)
+	if sliceValue.Kind() != reflect.Slice {
+		return goedbres, errors.New("InsertAll requires a slice")
+	}
+	if sliceValue.Len() == 0 {
+		return goedbres, nil
+	}
+	instances := make([]interface{}, sliceValue.Len())
+	for i := range instances {
+		if element := sliceValue.Index(i); element.Kind() == reflect.Ptr {
+			instances[i] = element.Interface()
+		} else {
+			instances[i] = element.Addr().Interface()
+		}
+	}
+	model, err := sqld.Model(instances[0])
 	if err != nil {
 		return goedbres, err
 	}
+	restore := make([]func(), 0, len(instances))
+	defer func() {
+		restoreOnError(&err, restore...)
+	}()
+
+	//The hooks run inside the transaction, so their changes are rolled back with the rows
+	err = sqld.TransactionContext(ctx, nil, func(tx EntityManager) error {
+		for _, instance := range instances {
+			if err := tx.(*SQLDatabase).runHook(beforeInsert(instance)); err != nil {
+				return err
+			}
+			restore = append(restore, sqld.stamp(model, instance, true))
+		}
+		batches, err := sqld.DBAccess.InsertAll(model, instances)
+		if err != nil {
+			return err
+		}
+		for _, batch := range batches {
+			result, err := tx.(*SQLDatabase).insertBatch(ctx, model, batch)
+			if err != nil {
+				return err
+			}
+			goedbres.NumRecordsAffected += result.NumRecordsAffected
+			goedbres.LastInsertId = result.LastInsertId
+		}
+		for _, instance := range instances {
+			if err := tx.(*SQLDatabase).runHook(afterInsert(instance)); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	return goedbres, err
+}
+
+// insertBatch runs a multi-row INSERT and writes the generated values back into the instances of the batch
+func (sqld *SQLDatabase) insertBatch(ctx context.Context, model models.Table, batch dbaccess.Batch) (goedbres models.Result, err error) {
+	generated := sqld.DBAccess.Generated(model, batch.Instances[0])
+
+	ctx, cancel := sqld.withTimeout(ctx)
+	defer cancel()
+
+	if batch.Returning {
+		rows, err := sqld.executor().QueryxContext(ctx, sqld.DBAccess.Rebind(batch.SQL), batch.Args...)
+		if err != nil {
+			return goedbres, err
+		}
+		defer rows.Close()
+		for rows.Next() && goedbres.NumRecordsAffected < int64(len(batch.Instances)) {
+			instance := batch.Instances[goedbres.NumRecordsAffected]
+			if err = rows.Scan(getFieldAddresses(instance, generated)...); err != nil {
+				return goedbres, err
+			}
+			goedbres.NumRecordsAffected++
+			goedbres.LastInsertId = getAutoIncrementValue(instance, generated)
+		}
+		return goedbres, rows.Err()
+	}
 
+	result, err := sqld.executor().ExecContext(ctx, sqld.DBAccess.Rebind(batch.SQL), batch.Args...)
+	if err != nil {
+		return goedbres, err
+	}
 	goedbres.NumRecordsAffected, _ = result.RowsAffected()
 	goedbres.LastInsertId, _ = result.LastInsertId()
+	for _, column := range generated {
+		if !column.AutoIncrement {
+			continue
+		}
+		if ids, ok := sqld.DBAccess.InsertedIDs(goedbres.LastInsertId, len(batch.Instances)); ok {
+			for i, instance := range batch.Instances {
+				setIntValue(models.GetValue(instance).FieldByName(column.Field), ids[i])
+			}
+		}
+	}
 	return goedbres, nil
 }
 
+// Upsert inserts the object or, if a row with the same conflict columns already exists, updates it in a single sentence.
+// The conflict columns must be primary keys or unique columns, if they are not set the primary keys are used or,
+// if they are generated by the database, the first unique column. The generated values are written back into the instance.
+// The entities with a version column cannot be upserted, the version of the row found would not be checked
+func (sqld *SQLDatabase) Upsert(instance interface{}, conflictColumns ...string) (models.Result, error) {
+	return sqld.UpsertContext(context.Background(), instance, conflictColumns...)
+}
+
+// UpsertContext inserts the object or, if a row with the same conflict columns already exists, updates it in a single sentence
+func (sqld *SQLDatabase) UpsertContext(ctx context.Context, instance interface{}, conflictColumns ...string) (goedbres models.Result, err error) {
+	model, err := sqld.Model(instance)
+	if err != nil {
+		return goedbres, err
+	}
+	if _, ok := models.GetVersionColumn(model); ok {
+		return goedbres, errors.New("Entity " + model.Name + " has a version column, it cannot be upserted")
+	}
+	conflictColumns, err = sqld.DBAccess.ConflictColumns(model, instance, conflictColumns)
+	if err != nil {
+		return goedbres, err
+	}
+	if err = sqld.runHook(beforeInsert(instance)); err != nil {
+		return goedbres, err
+	}
+	defer func() {
+		if err == nil {
+			err = sqld.runHook(afterInsert(instance))
+		}
+	}()
+
+	defer restoreOnError(&err, sqld.stamp(model, instance, true))
+	query, args, returning, err := sqld.DBAccess.Upsert(model, instance, conflictColumns)
+	if err != nil {
+		return goedbres, err
+	}
+	generated := sqld.DBAccess.Generated(model, instance)
+
+	ctx, cancel := sqld.withTimeout(ctx)
+	defer cancel()
+
+	if returning {
+		err = sqld.executor().QueryRowxContext(ctx, sqld.DBAccess.Rebind(query), args...).Scan(getFieldAddresses(instance, generated)...)
+		if err == nil {
+			goedbres.NumRecordsAffected = 1
+			goedbres.LastInsertId = getAutoIncrementValue(instance, generated)
+			return goedbres, nil
+		}
+		// The row is not returned when it is kept as it is, it is read using the conflict columns
+		if err != sql.ErrNoRows {
+			return goedbres, err
+		}
+	} else {
+		result, err := sqld.executor().ExecContext(ctx, sqld.DBAccess.Rebind(query), args...)
+		if err != nil {
+			return goedbres, err
+		}
+		goedbres.NumRecordsAffected, _ = result.RowsAffected()
+		if len(generated) == 0 {
+			return goedbres, nil
+		}
+	}
+
+	// The last insert id is not set when the row is updated, so the generated columns are read using the conflict columns
+	query, args, err = sqld.DBAccess.UpsertedRow(model, generated, conflictColumns, instance)
+	if err != nil {
+		return goedbres, err
+	}
+	err = sqld.executor().QueryRowxContext(ctx, sqld.DBAccess.Rebind(query), args...).Scan(getFieldAddresses(instance, generated)...)
+	goedbres.LastInsertId = getAutoIncrementValue(instance, generated)
+	return goedbres, err
+}
+
 // Update updates an object using its primery key
-func (sqld *SQLDatabase) Update(instance interface{}) (goedbres models.Result, err error) {
-	var result sql.Result
+func (sqld *SQLDatabase) Update(instance interface{}) (models.Result, error) {
+	return sqld.UpdateContext(context.Background(), instance)
+}
+
+// UpdateContext updates an object using its primery key
+func (sqld *SQLDatabase) UpdateContext(ctx context.Context, instance interface{}) (goedbres models.Result, err error) {
 	model, err := sqld.Model(instance)
 	if err != nil {
 		return goedbres, err
 	}
+	if err = sqld.runHook(beforeUpdate(instance)); err != nil {
+		return goedbres, err
+	}
+	defer func() {
+		if err == nil {
+			err = sqld.runHook(afterUpdate(instance))
+		}
+	}()
 
-	sql, err := sqld.DBAccess.Update(model, instance)
-	fmt.Println("UPDATE SQL: ", sql)
+	if sqld.Snapshots == nil {
+		defer restoreOnError(&err, sqld.stamp(model, instance, false))
+		sql, args, err := sqld.DBAccess.Update(model, instance)
+		if err != nil {
+			return goedbres, err
+		}
+		return sqld.execUpdate(ctx, model, instance, sql, args)
+	}
+
+	key, current, err := sqld.DBAccess.Snapshot(model, instance)
 	if err != nil {
 		return goedbres, err
 	}
-	result, err = sqld.db.Exec(sql)
+	var sql string
+	var args []interface{}
+	if snapshot, ok := sqld.Snapshots.get(model.Name, key, instance); ok {
+		changed := changedColumns(model, snapshot, current)
+		if len(changed) == 0 {
+			return goedbres, nil
+		}
+		defer restoreOnError(&err, sqld.stamp(model, instance, false))
+		sql, args, err = sqld.DBAccess.UpdateColumns(model, instance, changed)
+	} else {
+		defer restoreOnError(&err, sqld.stamp(model, instance, false))
+		sql, args, err = sqld.DBAccess.Update(model, instance)
+	}
 	if err != nil {
 		return goedbres, err
 	}
+	goedbres, err = sqld.execUpdate(ctx, model, instance, sql, args)
+	if err == nil && goedbres.NumRecordsAffected > 0 {
+		sqld.Snapshots.set(model.Name, key, instance, current)
+	}
+	return goedbres, err
+}
 
-	goedbres.NumRecordsAffected, _ = result.RowsAffected()
+// UpdateFields updates only the columns given (names of the columns or of their fields) of the row of the instance
+func (sqld *SQLDatabase) UpdateFields(instance interface{}, columns ...string) (models.Result, error) {
+	return sqld.UpdateFieldsContext(context.Background(), instance, columns...)
+}
+
+// UpdateFieldsContext updates only the columns given (names of the columns or of their fields) of the row of the instance
+func (sqld *SQLDatabase) UpdateFieldsContext(ctx context.Context, instance interface{}, columns ...string) (goedbres models.Result, err error) {
+	model, err := sqld.Model(instance)
+	if err != nil {
+		return goedbres, err
+	}
+	if err = sqld.runHook(beforeUpdate(instance)); err != nil {
+		return goedbres, err
+	}
+	defer func() {
+		if err == nil {
+			err = sqld.runHook(afterUpdate(instance))
+		}
+	}()
+
+	defer restoreOnError(&err, sqld.stamp(model, instance, false))
+	sql, args, err := sqld.DBAccess.UpdateColumns(model, instance, columns)
+	if err != nil {
+		return goedbres, err
+	}
+	goedbres, err = sqld.execUpdate(ctx, model, instance, sql, args)
+	if err == nil && sqld.Snapshots != nil {
+		sqld.rememberColumns(model, instance, columns)
+	}
+	return goedbres, err
+}
+
+// now returns the time of the clock in UTC, truncated to microseconds to keep the precision of the databases
+func (sqld *SQLDatabase) now() time.Time {
+	if sqld.Clock == nil {
+		return time.Now().UTC().Truncate(time.Microsecond)
+	}
+	return sqld.Clock().UTC().Truncate(time.Microsecond)
+}
+
+// stamp sets the updatedAt columns of an entity to the time of the clock, on insert it also sets the createdAt
+// columns that are not set yet. It returns a function that sets back the previous values, used when the sentence fails
+func (sqld *SQLDatabase) stamp(model models.Table, instance interface{}, insert bool) (restore func()) {
+	var now time.Time
+	fields := make([]reflect.Value, 0)
+	previous := make([]reflect.Value, 0)
+	for _, column := range model.Columns {
+		if column.Ignore || !(column.UpdatedAt || (insert && column.CreatedAt)) {
+			continue
+		}
+		if now.IsZero() {
+			now = sqld.now()
+		}
+		field := models.GetValue(instance).FieldByName(column.Field)
+		if !field.CanSet() {
+			continue
+		}
+		value := reflect.New(field.Type()).Elem()
+		value.Set(field)
+		fields = append(fields, field)
+		previous = append(previous, value)
+		models.SetTimestamp(field, now, column.CreatedAt)
+	}
+	return func() {
+		for i, field := range fields {
+			field.Set(previous[i])
+		}
+	}
+}
+
+// restoreOnError calls the restore functions if the operation failed
+func restoreOnError(err *error, restore ...func()) {
+	if *err == nil {
+		return
+	}
+	for _, restore := range restore {
+		restore()
+	}
+}
+
+// execUpdate runs the update of an entity. If the entity has a version column, ErrStaleEntity is returned when
+// no row was updated, otherwise the version of the entity is incremented
+func (sqld *SQLDatabase) execUpdate(ctx context.Context, model models.Table, instance interface{}, sql string, args []interface{}) (goedbres models.Result, err error) {
+	goedbres, err = sqld.execWhere(ctx, sql, args)
+	if err != nil {
+		return goedbres, err
+	}
+	if version, ok := models.GetVersionColumn(model); ok {
+		if goedbres.NumRecordsAffected == 0 {
+			return goedbres, ErrStaleEntity
+		}
+		models.IncrementVersion(models.GetValue(instance).FieldByName(version.Field))
+	}
 	return goedbres, nil
 }
 
+// UpdateWhere sets the columns of values in every row of the model of i matching the where clause, the keys of
+// values are the names of the columns and i is only used to find the model
+func (sqld *SQLDatabase) UpdateWhere(i interface{}, values map[string]interface{}, where string, params map[string]interface{}) (models.Result, error) {
+	return sqld.UpdateWhereContext(context.Background(), i, values, where, params)
+}
+
+// UpdateWhereContext sets the columns of values in every row of the model of i matching the where clause
+func (sqld *SQLDatabase) UpdateWhereContext(ctx context.Context, i interface{}, values map[string]interface{}, where string, params map[string]interface{}) (goedbres models.Result, err error) {
+	model, err := sqld.Model(i)
+	if err != nil {
+		return goedbres, err
+	}
+
+	for _, column := range model.Columns {
+		if column.UpdatedAt && !column.Ignore {
+			_, titleSet := values[column.Title]
+			if _, fieldSet := values[column.Field]; !titleSet && !fieldSet {
+				stamped := make(map[string]interface{}, len(values)+1)
+				for name, value := range values {
+					stamped[name] = value
+				}
+				stamped[column.Title] = sqld.now()
+				values = stamped
+			}
+		}
+	}
+	sql, args, err := sqld.DBAccess.UpdateWhere(model, values, where, params)
+	if err != nil {
+		return goedbres, err
+	}
+	sqld.forget(model)
+	return sqld.execWhere(ctx, sql, args)
+}
+
 // Remove removes a row with the object in the database (it must be migrated)
-func (sqld *SQLDatabase) Remove(i interface{}, where string, params map[string]interface{}) (goedbres models.Result, err error) {
+func (sqld *SQLDatabase) Remove(i interface{}, where string, params map[string]interface{}) (models.Result, error) {
+	return sqld.RemoveContext(context.Background(), i, where, params)
+}
+
+// RemoveContext removes a row with the object in the database (it must be migrated)
+func (sqld *SQLDatabase) RemoveContext(ctx context.Context, i interface{}, where string, params map[string]interface{}) (goedbres models.Result, err error) {
 	model, err := sqld.Model(i)
 	if err != nil {
 		return goedbres, err
 	}
+	if where == "" {
+		if err = sqld.runHook(beforeRemove(i)); err != nil {
+			return goedbres, err
+		}
+		defer func() {
+			if err == nil {
+				err = sqld.runHook(afterRemove(i))
+			}
+		}()
+	}
 
-	sql, err := sqld.DBAccess.Delete(model, where, i)
+	var sql string
+	var args []interface{}
+	_, softDelete := models.GetSoftDeleteColumn(model)
+	now := sqld.now()
+	if softDelete && !sqld.unscoped {
+		sql, args, err = sqld.DBAccess.SoftDelete(model, where, params, i, now)
+	} else {
+		sql, args, err = sqld.DBAccess.Delete(model, where, params, i)
+	}
 	if err != nil {
 		return goedbres, err
 	}
+	sqld.forget(model)
+	goedbres, err = sqld.execWhere(ctx, sql, args)
+	if err == nil && where == "" && softDelete && !sqld.unscoped {
+		setDeleted(model, i, now)
+	}
+	return goedbres, err
+}
 
-	result, err := sqld.db.NamedExec(sql, params)
-	goedbres.NumRecordsAffected, _ = result.RowsAffected()
+// RemoveWhere removes every row of the model of i matching the where clause, i is only used to find the model
+func (sqld *SQLDatabase) RemoveWhere(i interface{}, where string, params map[string]interface{}) (models.Result, error) {
+	return sqld.RemoveWhereContext(context.Background(), i, where, params)
+}
+
+// RemoveWhereContext removes every row of the model of i matching the where clause
+func (sqld *SQLDatabase) RemoveWhereContext(ctx context.Context, i interface{}, where string, params map[string]interface{}) (goedbres models.Result, err error) {
+	model, err := sqld.Model(i)
+	if err != nil {
+		return goedbres, err
+	}
+
+	sql, args, err := sqld.DBAccess.DeleteWhere(model, where, params)
+	if err != nil {
+		return goedbres, err
+	}
+	if _, ok := models.GetSoftDeleteColumn(model); ok && !sqld.unscoped {
+		if sql, args, err = sqld.DBAccess.SoftDelete(model, where, params, nil, sqld.now()); err != nil {
+			return goedbres, err
+		}
+	}
+	sqld.forget(model)
+	return sqld.execWhere(ctx, sql, args)
+}
+
+// Restore unmarks as removed the row of an entity with a softDelete column using its primary key
+func (sqld *SQLDatabase) Restore(i interface{}) (models.Result, error) {
+	return sqld.RestoreContext(context.Background(), i)
+}
+
+// RestoreContext unmarks as removed the row of an entity with a softDelete column using its primary key
+func (sqld *SQLDatabase) RestoreContext(ctx context.Context, i interface{}) (goedbres models.Result, err error) {
+	model, err := sqld.Model(i)
+	if err != nil {
+		return goedbres, err
+	}
+
+	sql, args, err := sqld.DBAccess.Restore(model, "", nil, i)
+	if err != nil {
+		return goedbres, err
+	}
+	goedbres, err = sqld.execWhere(ctx, sql, args)
+	if err == nil {
+		setDeleted(model, i, time.Time{})
+	}
 	return goedbres, err
 }
 
+// Unscoped returns an entity manager whose queries include the rows soft deleted and whose Remove and RemoveWhere
+// delete the rows for good
+func (sqld *SQLDatabase) Unscoped() EntityManager {
+	unscoped := *sqld
+	unscoped.DBAccess = sqld.DBAccess.Unscoped()
+	unscoped.unscoped = true
+	return &unscoped
+}
+
+// setDeleted sets the softDelete field of an entity to the time it was removed, or to true if it is a boolean.
+// The zero time unsets it
+func setDeleted(model models.Table, instance interface{}, deletedAt time.Time) {
+	column, ok := models.GetSoftDeleteColumn(model)
+	if !ok {
+		return
+	}
+	field := models.GetValue(instance).FieldByName(column.Field)
+	if !field.CanSet() {
+		return
+	}
+	switch {
+	case field.Kind() == reflect.Bool:
+		field.SetBool(!deletedAt.IsZero())
+	case deletedAt.IsZero():
+		field.Set(reflect.Zero(field.Type()))
+	default:
+		models.SetTimestamp(field, deletedAt, false)
+	}
+}
+
+func (sqld *SQLDatabase) execWhere(ctx context.Context, sql string, args []interface{}) (goedbres models.Result, err error) {
+	ctx, cancel := sqld.withTimeout(ctx)
+	defer cancel()
+	result, err := sqld.executor().ExecContext(ctx, sqld.DBAccess.Rebind(sql), args...)
+	if err != nil {
+		return goedbres, err
+	}
+	goedbres.NumRecordsAffected, _ = result.RowsAffected()
+	return goedbres, nil
+}
+
+// remember keeps the snapshot of an entity loaded if the entity manager has snapshots and the entity embeds Tracked
+func (sqld *SQLDatabase) remember(model models.Table, instance interface{}) {
+	if _, ok := instance.(tracker); sqld.Snapshots == nil || !ok {
+		return
+	}
+	if key, values, err := sqld.DBAccess.Snapshot(model, instance); err == nil {
+		sqld.Snapshots.set(model.Name, key, instance, values)
+	}
+}
+
+// rememberColumns updates the snapshot of an entity with the values of the columns written, if the entity has one
+func (sqld *SQLDatabase) rememberColumns(model models.Table, instance interface{}, columns []string) {
+	key, current, err := sqld.DBAccess.Snapshot(model, instance)
+	if err != nil {
+		return
+	}
+	snapshot, ok := sqld.Snapshots.get(model.Name, key, instance)
+	if !ok {
+		return
+	}
+	merged := make(map[string]interface{}, len(snapshot))
+	for column, value := range snapshot {
+		merged[column] = value
+	}
+	for _, column := range model.Columns {
+		for _, name := range columns {
+			if strings.EqualFold(column.Title, name) || column.Field == name {
+				merged[column.Title] = current[column.Title]
+			}
+		}
+	}
+	sqld.Snapshots.set(model.Name, key, instance, merged)
+}
+
+// forget removes the snapshots of a table, the rows may have been changed without the entities
+func (sqld *SQLDatabase) forget(model models.Table) {
+	if sqld.Snapshots != nil {
+		sqld.Snapshots.forget(model.Name)
+	}
+}
+
 // First returns the first record found
 func (sqld *SQLDatabase) First(instance interface{}, where string, params map[string]interface{}) error {
+	return sqld.FirstContext(context.Background(), instance, where, params)
+}
+
+// FirstContext returns the first record found
+func (sqld *SQLDatabase) FirstContext(ctx context.Context, instance interface{}, where string, params map[string]interface{}) error {
 	model, err := sqld.Model(instance)
 	if err != nil {
 		return err
 	}
 
-	sql, err := sqld.DBAccess.First(model, where, instance)
+	sql, args, err := sqld.DBAccess.First(model, where, params, instance)
 	if err != nil {
 		return err
 	}
-	rows, err := sqld.db.NamedQuery(sql, params)
+	return sqld.queryFirst(ctx, model, instance, sql, args)
+}
+
+// queryFirst runs the query and scans the first row into the instance
+func (sqld *SQLDatabase) queryFirst(ctx context.Context, model models.Table, instance interface{}, sql string, args []interface{}) error {
+	ctx, cancel := sqld.withTimeout(ctx)
+	defer cancel()
+	rows, err := sqld.executor().QueryxContext(ctx, sqld.DBAccess.Rebind(sql), args...)
 	if err != nil {
 		return err
 	}
 	defer rows.Close()
-	if rows.Next() {
-		instanceValuesAddresses := models.StructToSliceOfAddressesWithRules(instance, sqld.DBAccess.GetModel)
-		err = rows.Scan(instanceValuesAddresses...)
-	} else {
-		err = errors.New("Not found")
+	if !rows.Next() {
+		if err = rows.Err(); err != nil {
+			return err
+		}
+		return errors.New("Not found")
 	}
-	return err
+	instanceValuesAddresses := models.StructToSliceOfAddressesWithRules(instance, sqld.DBAccess.GetModel)
+	if err = rows.Scan(instanceValuesAddresses...); err != nil {
+		return err
+	}
+	//The rows are closed before the hook runs, so it can use the connection of the transaction
+	if err = rows.Close(); err != nil {
+		return err
+	}
+	sqld.remember(model, instance)
+	return sqld.runHook(afterFind(instance))
 }
 
 // NativeFirst returns the first record found
 func (sqld *SQLDatabase) NativeFirst(instance interface{}, sql string, params map[string]interface{}) error {
-	rows, err := sqld.db.NamedQuery(sql, params)
+	return sqld.NativeFirstContext(context.Background(), instance, sql, params)
+}
+
+// NativeFirstContext returns the first record found
+func (sqld *SQLDatabase) NativeFirstContext(ctx context.Context, instance interface{}, sql string, params map[string]interface{}) error {
+	ctx, cancel := sqld.withTimeout(ctx)
+	defer cancel()
+	rows, err := sqlx.NamedQueryContext(ctx, sqld.executor(), sql, params)
 	if err != nil {
 		return err
 	}
@@ -192,6 +874,11 @@ func (sqld *SQLDatabase) NativeFirst(instance interface{}, sql string, params ma
 
 // Find returns all records found
 func (sqld *SQLDatabase) Find(instance interface{}, where string, params map[string]interface{}) error {
+	return sqld.FindContext(context.Background(), instance, where, params)
+}
+
+// FindContext returns all records found
+func (sqld *SQLDatabase) FindContext(ctx context.Context, instance interface{}, where string, params map[string]interface{}) error {
 
 	if reflect.TypeOf(instance).Elem().Kind() != reflect.Slice {
 		return errors.New("The intput value is not a pointer of a slice")
@@ -202,12 +889,18 @@ func (sqld *SQLDatabase) Find(instance interface{}, where string, params map[str
 		return err
 	}
 
-	sql, err := sqld.DBAccess.Find(model, where, instance)
+	sql, args, err := sqld.DBAccess.Find(model, where, params, instance)
 	if err != nil {
 		return err
 	}
+	return sqld.queryAll(ctx, model, instance, sql, args)
+}
 
-	rows, err := sqld.db.NamedQuery(sql, params)
+// queryAll runs the query and appends a new element to the slice pointed by instance for each row
+func (sqld *SQLDatabase) queryAll(ctx context.Context, model models.Table, instance interface{}, sql string, args []interface{}) error {
+	ctx, cancel := sqld.withTimeout(ctx)
+	defer cancel()
+	rows, err := sqld.executor().QueryxContext(ctx, sqld.DBAccess.Rebind(sql), args...)
 	if err != nil {
 		return err
 	}
@@ -221,14 +914,20 @@ func (sqld *SQLDatabase) Find(instance interface{}, where string, params map[str
 	entityType := models.GetType(instance)
 
 	if !rows.Next() {
+		if err := rows.Err(); err != nil {
+			return err
+		}
 		return errors.New("Records not found")
 	}
 
+	loaded := slice.Len()
 	for {
 		entityPtr := reflect.New(entityType)
 
 		entityFieldsAsSlice := models.StructToSliceOfAddressesWithRules(entityPtr, sqld.DBAccess.GetModel)
-		rows.Scan(entityFieldsAsSlice...)
+		if err := rows.Scan(entityFieldsAsSlice...); err != nil {
+			return err
+		}
 
 		slice.Set(reflect.Append(slice, entityPtr.Elem()))
 
@@ -237,17 +936,40 @@ func (sqld *SQLDatabase) Find(instance interface{}, where string, params map[str
 		}
 	}
 
+	//The snapshots are taken once the slice is complete, appending moves the elements
+	for i := loaded; i < slice.Len(); i++ {
+		sqld.remember(model, slice.Index(i).Addr().Interface())
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+	//The rows are closed before the hooks run, so they can use the connection of the transaction
+	if err := rows.Close(); err != nil {
+		return err
+	}
+	for i := loaded; i < slice.Len(); i++ {
+		if err := sqld.runHook(afterFind(slice.Index(i).Addr().Interface())); err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
 // NativeFind returns all records found
 func (sqld *SQLDatabase) NativeFind(resultEntitySlice interface{}, sql string, params map[string]interface{}) error {
+	return sqld.NativeFindContext(context.Background(), resultEntitySlice, sql, params)
+}
+
+// NativeFindContext returns all records found
+func (sqld *SQLDatabase) NativeFindContext(ctx context.Context, resultEntitySlice interface{}, sql string, params map[string]interface{}) error {
 
 	if reflect.TypeOf(resultEntitySlice).Elem().Kind() != reflect.Slice {
 		return errors.New("The intput value is not a pointer of a slice")
 	}
 
-	rows, err := sqld.db.NamedQuery(sql, params)
+	ctx, cancel := sqld.withTimeout(ctx)
+	defer cancel()
+	rows, err := sqlx.NamedQueryContext(ctx, sqld.executor(), sql, params)
 	if err != nil {
 		return err
 	}
@@ -268,7 +990,9 @@ func (sqld *SQLDatabase) NativeFind(resultEntitySlice interface{}, sql string, p
 		entityPtr := reflect.New(entityType)
 
 		entityFieldsAsSlice := models.StructToSliceOfAddresses(entityPtr)
-		rows.Scan(entityFieldsAsSlice...)
+		if err := rows.Scan(entityFieldsAsSlice...); err != nil {
+			return err
+		}
 
 		slice.Set(reflect.Append(slice, entityPtr.Elem()))
 
@@ -276,29 +1000,173 @@ func (sqld *SQLDatabase) NativeFind(resultEntitySlice interface{}, sql string, p
 			break
 		}
 	}
-	return nil
+	return rows.Err()
 }
 
 // DropTable removes a table from the database
 func (sqld *SQLDatabase) DropTable(i interface{}) error {
-	typ := models.GetType(i)
-	name := typ.Name()
+	return sqld.DropTableContext(context.Background(), i)
+}
+
+// DropTableContext removes a table from the database
+func (sqld *SQLDatabase) DropTableContext(ctx context.Context, i interface{}) error {
+	name := models.ModelKey(models.GetType(i))
 
 	table, ok := sqld.DBAccess.GetModel(name)
 	if !ok {
 		return errors.New("Model not found")
 	}
-	sql := sqld.DBAccess.Drop(table.Name)
+	sql := sqld.DBAccess.Drop(table.Name, false)
 
-	_, err := sqld.db.Exec(sql)
+	ctx, cancel := sqld.withTimeout(ctx)
+	defer cancel()
+	_, err := sqld.executor().ExecContext(ctx, sql)
 	if err != nil {
 		return err
 	}
 	sqld.DBAccess.DeleteModel(name)
+	sqld.forget(table)
 	return nil
 }
 
-// TxBegin is used to set a transaction
-func (sqld *SQLDatabase) TxBegin() (*sql.Tx, error) {
-	return sqld.db.Begin()
+// Tables returns the structure of the tables found in the database
+func (sqld *SQLDatabase) Tables() ([]models.TableSchema, error) {
+	return sqld.TablesContext(context.Background())
+}
+
+// TablesContext returns the structure of the tables found in the database
+func (sqld *SQLDatabase) TablesContext(ctx context.Context) ([]models.TableSchema, error) {
+	ctx, cancel := sqld.withTimeout(ctx)
+	defer cancel()
+	names, err := sqld.DBAccess.Tables(ctx, sqld.executor())
+	if err != nil {
+		return nil, err
+	}
+	tables := make([]models.TableSchema, 0, len(names))
+	for _, name := range names {
+		schema, err := sqld.DBAccess.Schema(ctx, sqld.executor(), name)
+		if err != nil {
+			return nil, err
+		}
+		tables = append(tables, schema)
+	}
+	return tables, nil
+}
+
+// TxBegin starts a transaction, it returns an entity manager whose operations run inside it
+func (sqld *SQLDatabase) TxBegin() (EntityManager, error) {
+	return sqld.TxBeginContext(context.Background(), nil)
+}
+
+// TxBeginContext starts a transaction, it returns an entity manager whose operations run inside it.
+// The transaction is rolled back if the context is done before it is committed
+func (sqld *SQLDatabase) TxBeginContext(ctx context.Context, opts *sql.TxOptions) (EntityManager, error) {
+	if sqld.tx != nil {
+		return nil, errors.New("Transaction already started")
+	}
+	tx, err := sqld.db.BeginTxx(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+	txManager := *sqld
+	txManager.tx = tx
+	txManager.Snapshots = sqld.Snapshots.begin()
+	return &txManager, nil
+}
+
+// Commit commits the transaction of a transaction-scoped entity manager
+func (sqld *SQLDatabase) Commit() error {
+	if sqld.tx == nil {
+		return errors.New("Transaction not started")
+	}
+	if err := sqld.tx.Commit(); err != nil {
+		sqld.Snapshots.rollback()
+		return err
+	}
+	sqld.Snapshots.commit()
+	return nil
+}
+
+// Rollback aborts the transaction of a transaction-scoped entity manager
+func (sqld *SQLDatabase) Rollback() error {
+	if sqld.tx == nil {
+		return errors.New("Transaction not started")
+	}
+	sqld.Snapshots.rollback()
+	return sqld.tx.Rollback()
+}
+
+// Transaction runs fn inside a transaction. The transaction is committed if fn returns nil,
+// otherwise, or if fn panics, it is rolled back. If the entity manager is already transaction-scoped,
+// fn runs inside the current transaction and it is not finished
+func (sqld *SQLDatabase) Transaction(fn func(tx EntityManager) error) error {
+	return sqld.TransactionContext(context.Background(), nil, fn)
+}
+
+// TransactionContext runs fn inside a transaction started with the context and options received.
+// The transaction is committed if fn returns nil, otherwise, or if fn panics, it is rolled back
+func (sqld *SQLDatabase) TransactionContext(ctx context.Context, opts *sql.TxOptions, fn func(tx EntityManager) error) (err error) {
+	if sqld.tx != nil {
+		return fn(sqld)
+	}
+	tx, err := sqld.TxBeginContext(ctx, opts)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if p := recover(); p != nil {
+			tx.Rollback()
+			panic(p)
+		}
+	}()
+	if err = fn(tx); err != nil {
+		tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
+
+// getFieldAddresses returns the addresses of the instance fields mapped by the columns.
+// If the instance is not a pointer the values are discarded
+func getFieldAddresses(instance interface{}, columns []models.Column) []interface{} {
+	value := models.GetValue(instance)
+	addresses := make([]interface{}, 0, len(columns))
+	for _, column := range columns {
+		if !value.CanAddr() {
+			addresses = append(addresses, new(interface{}))
+			continue
+		}
+		addresses = append(addresses, models.ColumnAddress(column, value.FieldByName(column.Field)))
+	}
+	return addresses
+}
+
+// getAutoIncrementValue returns the value of the autoincrement column or 0 if it is not found
+func getAutoIncrementValue(instance interface{}, columns []models.Column) int64 {
+	value := models.GetValue(instance)
+	for _, column := range columns {
+		if !column.AutoIncrement {
+			continue
+		}
+		field := value.FieldByName(column.Field)
+		switch field.Kind() {
+		case reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int, reflect.Int64:
+			return field.Int()
+		case reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint, reflect.Uint64:
+			return int64(field.Uint())
+		}
+	}
+	return 0
+}
+
+func setIntValue(field reflect.Value, value int64) {
+	if !field.CanSet() {
+		return
+	}
+	switch field.Kind() {
+	case reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int, reflect.Int64:
+		field.SetInt(value)
+	case reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint, reflect.Uint64:
+		field.SetUint(uint64(value))
+	}
 }
@@ -0,0 +1,165 @@
+package database
+
+import (
+	"reflect"
+	"sync"
+
+	"github.com/plopezm/goedb/database/models"
+)
+
+// Tracked is embedded in the entities whose original values are kept by the entity managers with snapshots. The snapshot
+// is stored in the entity itself, so it is released with the entity and two entities loaded from the same row remember
+// their own values. Tracked has no exported fields, so it is not stored as a column
+type Tracked struct {
+	snapshot *snapshot
+}
+
+func (tracked *Tracked) tracked() *Tracked {
+	return tracked
+}
+
+// tracker is implemented by the pointers to the entities embedding Tracked
+type tracker interface {
+	tracked() *Tracked
+}
+
+// snapshot holds the values of a row when its entity was loaded or updated
+type snapshot struct {
+	owner      *Snapshots
+	table      string
+	key        string
+	generation uint64
+	values     map[string]interface{}
+}
+
+// Snapshots makes an entity manager keep the original values of the entities loaded with First and Find, when the entities
+// embed Tracked. Update only writes the columns whose values changed since the entity was loaded or updated.
+// The snapshots of a table are discarded when its rows are changed without the entities, and the snapshots taken inside
+// a transaction are discarded on Rollback
+type Snapshots struct {
+	mutex      sync.Mutex
+	parent     *Snapshots
+	generation uint64
+	cleared    uint64
+	tables     map[string]uint64
+	undo       []func()
+}
+
+// NewSnapshots returns an empty set of snapshots
+func NewSnapshots() *Snapshots {
+	return &Snapshots{tables: make(map[string]uint64)}
+}
+
+// Clear discards every snapshot, the entities loaded before are updated writing all their columns
+func (snapshots *Snapshots) Clear() {
+	root := snapshots.root()
+	root.mutex.Lock()
+	defer root.mutex.Unlock()
+	root.generation++
+	root.cleared = root.generation
+	root.tables = make(map[string]uint64)
+}
+
+// root returns the snapshots of the entity manager, the ones of a transaction belong to its parent
+func (snapshots *Snapshots) root() *Snapshots {
+	for snapshots.parent != nil {
+		snapshots = snapshots.parent
+	}
+	return snapshots
+}
+
+// current returns the generation of the snapshots of a table, the older ones are discarded
+func (snapshots *Snapshots) current(table string) uint64 {
+	root := snapshots.root()
+	root.mutex.Lock()
+	defer root.mutex.Unlock()
+	if generation := root.tables[table]; generation > root.cleared {
+		return generation
+	}
+	return root.cleared
+}
+
+// get returns the snapshot of an entity, if it was taken by these snapshots from the same row and it was not discarded
+func (snapshots *Snapshots) get(table string, key string, instance interface{}) (map[string]interface{}, bool) {
+	entity, ok := instance.(tracker)
+	if !ok {
+		return nil, false
+	}
+	saved := entity.tracked().snapshot
+	if saved == nil || saved.owner != snapshots.root() || saved.table != table || saved.key != key || saved.generation != snapshots.current(table) {
+		return nil, false
+	}
+	return saved.values, true
+}
+
+// set keeps the snapshot of an entity, it does nothing if the entity does not embed Tracked.
+// Inside a transaction the previous snapshot is restored on Rollback
+func (snapshots *Snapshots) set(table string, key string, instance interface{}, values map[string]interface{}) {
+	entity, ok := instance.(tracker)
+	if !ok {
+		return
+	}
+	tracked := entity.tracked()
+	previous := tracked.snapshot
+	tracked.snapshot = &snapshot{owner: snapshots.root(), table: table, key: key, generation: snapshots.current(table), values: values}
+	if snapshots.parent != nil {
+		snapshots.mutex.Lock()
+		snapshots.undo = append(snapshots.undo, func() { tracked.snapshot = previous })
+		snapshots.mutex.Unlock()
+	}
+}
+
+// forget discards the snapshots of every row of a table, it is used when the rows are changed without the entities
+func (snapshots *Snapshots) forget(table string) {
+	root := snapshots.root()
+	root.mutex.Lock()
+	defer root.mutex.Unlock()
+	root.generation++
+	root.tables[table] = root.generation
+}
+
+// begin returns the snapshots of a transaction
+func (snapshots *Snapshots) begin() *Snapshots {
+	if snapshots == nil {
+		return nil
+	}
+	return &Snapshots{parent: snapshots}
+}
+
+// commit keeps the snapshots taken by a transaction
+func (snapshots *Snapshots) commit() {
+	if snapshots == nil || snapshots.parent == nil {
+		return
+	}
+	snapshots.mutex.Lock()
+	defer snapshots.mutex.Unlock()
+	snapshots.undo = nil
+}
+
+// rollback restores the snapshots the entities had before the transaction
+func (snapshots *Snapshots) rollback() {
+	if snapshots == nil || snapshots.parent == nil {
+		return
+	}
+	snapshots.mutex.Lock()
+	defer snapshots.mutex.Unlock()
+	for i := len(snapshots.undo) - 1; i >= 0; i-- {
+		snapshots.undo[i]()
+	}
+	snapshots.undo = nil
+}
+
+// changedColumns returns the columns whose current values are different from the snapshot, in the order of the table
+func changedColumns(table models.Table, snapshot map[string]interface{}, current map[string]interface{}) []string {
+	changed := make([]string, 0)
+	for _, column := range table.Columns {
+		value, ok := current[column.Title]
+		if !ok {
+			continue
+		}
+		if original, ok := snapshot[column.Title]; !ok || !reflect.DeepEqual(original, value) {
+			changed = append(changed, column.Title)
+		}
+	}
+	return changed
+}
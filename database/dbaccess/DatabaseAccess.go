@@ -1,28 +1,55 @@
 package dbaccess
 
-import "github.com/plopezm/goedb/database/models"
+import (
+	"context"
+	"time"
 
-// DatabaseAccess database access layer functions (could be a sql dbaccess or no-sql database)
+	"github.com/jmoiron/sqlx"
+	"github.com/plopezm/goedb/database/models"
+)
+
+// DatabaseAccess database access layer functions (could be a sql dbaccess or no-sql database).
+// Sentences are returned using "?" as bindvar together with the arguments to bind, the data values
+// are never written into the sentence.
 type DatabaseAccess interface {
 	GetModel(name string) (models.Table, bool)
 	SetModel(name string, table models.Table)
 	DeleteModel(name string)
-	Create(table models.Table) string
-	Insert(table models.Table, instance interface{}) (string, error)
-	First(table models.Table, where string, instance interface{}) (string, error)
-	Find(table models.Table, where string, instance interface{}) (string, error)
-	Update(table models.Table, instance interface{}) (string, error)
-	Delete(table models.Table, where string, instance interface{}) (string, error)
-	Drop(tableName string) string
+	Create(table models.Table) (string, error)
+	Schema(ctx context.Context, db sqlx.QueryerContext, tableName string) (models.TableSchema, error)
+	Tables(ctx context.Context, db sqlx.QueryerContext) ([]string, error)
+	Indexes(table models.Table, schema models.TableSchema) []string
+	Alter(table models.Table, schema models.TableSchema) (Migration, error)
+	Insert(table models.Table, instance interface{}) (string, []interface{}, bool, error)
+	Generated(table models.Table, instance interface{}) []models.Column
+	InsertedRow(table models.Table, generated []models.Column, instance interface{}, lastInsertID int64) (string, []interface{}, bool)
+	InsertAll(table models.Table, instances []interface{}) ([]Batch, error)
+	InsertedIDs(lastInsertID int64, rows int) ([]int64, bool)
+	ConflictColumns(table models.Table, instance interface{}, columns []string) ([]string, error)
+	Upsert(table models.Table, instance interface{}, conflictColumns []string) (string, []interface{}, bool, error)
+	UpsertedRow(table models.Table, generated []models.Column, conflictColumns []string, instance interface{}) (string, []interface{}, error)
+	First(table models.Table, where string, params map[string]interface{}, instance interface{}) (string, []interface{}, error)
+	Find(table models.Table, where string, params map[string]interface{}, instance interface{}) (string, []interface{}, error)
+	Select(table models.Table, query Query) (string, []interface{}, error)
+	JSONPath(column string, path []string, value interface{}) (string, error)
+	Update(table models.Table, instance interface{}) (string, []interface{}, error)
+	UpdateColumns(table models.Table, instance interface{}, columns []string) (string, []interface{}, error)
+	Snapshot(table models.Table, instance interface{}) (string, map[string]interface{}, error)
+	UpdateWhere(table models.Table, values map[string]interface{}, where string, params map[string]interface{}) (string, []interface{}, error)
+	Delete(table models.Table, where string, params map[string]interface{}, instance interface{}) (string, []interface{}, error)
+	DeleteWhere(table models.Table, where string, params map[string]interface{}) (string, []interface{}, error)
+	SoftDelete(table models.Table, where string, params map[string]interface{}, instance interface{}, deletedAt time.Time) (string, []interface{}, error)
+	Restore(table models.Table, where string, params map[string]interface{}, instance interface{}) (string, []interface{}, error)
+	Unscoped() DatabaseAccess
+	Drop(tableName string, ifExists bool) string
+	Rebind(sql string) string
 }
 
-// GetDatabaseAccess returns the database depending on the driver used (could be a sql dbaccess or no-sql database)
-func GetDatabaseAccess(driver string) (databaseAccess DatabaseAccess) {
-	switch driver {
-	case "sqlite3", "postgres", "pgx":
-		databaseAccess = GetSQLDatabaseAccess(driver)
-	default:
-		databaseAccess = GetSQLDatabaseAccess(driver)
+// GetDatabaseAccess returns the database access of a driver, an error is returned if the driver has no dialect registered
+func GetDatabaseAccess(driver string) (DatabaseAccess, error) {
+	databaseAccess, err := GetSQLDatabaseAccess(driver)
+	if err != nil {
+		return nil, err
 	}
-	return databaseAccess
+	return databaseAccess, nil
 }
@@ -1,33 +1,76 @@
 package dbaccess
 
 import (
+	"context"
+	"database/sql/driver"
 	"errors"
+	"fmt"
 	"reflect"
-	"strconv"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
 
+	"github.com/jmoiron/sqlx"
 	"github.com/plopezm/goedb/database/dbaccess/dialect"
 	"github.com/plopezm/goedb/database/models"
 )
 
-//GetSQLDatabaseAccess returns the standard SQL dbaccess with the some small modifications required
-func GetSQLDatabaseAccess(driverSpecifics string) (databaseAccess *SQLDatabaseAccess) {
-	databaseAccess = new(SQLDatabaseAccess)
-	databaseAccess.Models = make(map[string]models.Table)
-	switch driverSpecifics {
-	case "sqlite3":
-		databaseAccess.Dialect = new(dialect.SQLite3Dialect)
-	case "postgres", "pgx":
-		databaseAccess.Dialect = new(dialect.PostgresDialect)
-	default:
-		databaseAccess.Dialect = new(dialect.SQLite3Dialect)
+//GetSQLDatabaseAccess returns the standard SQL dbaccess using the dialect registered for the driver
+func GetSQLDatabaseAccess(driverName string) (*SQLDatabaseAccess, error) {
+	specifics, err := dialect.Get(driverName)
+	if err != nil {
+		return nil, err
 	}
-	return databaseAccess
+	databaseAccess := new(SQLDatabaseAccess)
+	databaseAccess.Models = make(map[string]models.Table)
+	databaseAccess.Dialect = specifics
+	return databaseAccess, nil
+}
+
+//Query contains the clauses used to select the rows of a table, Limit and Offset are ignored if they are 0
+type Query struct {
+	Where   string
+	Params  map[string]interface{}
+	OrderBy string
+	Limit   int
+	Offset  int
+	// WithDeleted includes the rows soft deleted
+	WithDeleted bool
+}
+
+var jsonPathElement = regexp.MustCompile(`^[A-Za-z0-9_]+$`)
+
+//Migration contains the sentences required to migrate an existing table to its model
+type Migration struct {
+	Sentences []string
+	//Rebuild is true when the table is copied into a new one, the sentences must run in a transaction
+	//between ForeignKeysOff and ForeignKeysOn, otherwise the rows referencing the table are deleted with it
+	Rebuild        bool
+	ForeignKeysOff string
+	ForeignKeysOn  string
+}
+
+//Batch is a multi-row INSERT sentence, Instances are the instances inserted by it in the same order as its rows
+type Batch struct {
+	SQL       string
+	Args      []interface{}
+	Instances []interface{}
+	//Returning is true when the sentence returns the generated columns of each row, it must be run as a query
+	Returning bool
 }
 
 //SQLDatabaseAccess is the implementation of Transient SQL as DatabaseAccess
 type SQLDatabaseAccess struct {
 	Models  map[string]models.Table
 	Dialect dialect.Dialect
+	// withDeleted includes the rows soft deleted in every sentence, it is set in the copies returned by Unscoped
+	withDeleted bool
+}
+
+//Unscoped returns a copy of the database access, sharing its models, whose sentences include the rows soft deleted
+func (dialect *SQLDatabaseAccess) Unscoped() DatabaseAccess {
+	return &SQLDatabaseAccess{Models: dialect.Models, Dialect: dialect.Dialect, withDeleted: true}
 }
 
 //GetModel returns the stored model
@@ -46,21 +89,31 @@ func (dialect *SQLDatabaseAccess) DeleteModel(name string) {
 	delete(dialect.Models, name)
 }
 
-//Create generates the SQL CREATE TABLE using a goedb table
-func (dialect *SQLDatabaseAccess) Create(table models.Table) string {
+//Create generates the SQL CREATE TABLE using a goedb table, an error is returned if the type of a column is unknown
+func (dialect *SQLDatabaseAccess) Create(table models.Table) (string, error) {
+	return dialect.createTable(table, "")
+}
+
+//createTable generates the SQL CREATE TABLE, extraColumns are added after the columns of the model.
+//The ignored columns are skipped, an error is returned if the type of another column is unknown
+func (dialect *SQLDatabaseAccess) createTable(table models.Table, extraColumns string) (string, error) {
 	columns := ""
 	pksFound := ""
 	constraints := ""
 
 	for _, value := range table.Columns {
+		if value.Ignore {
+			continue
+		}
 		columnModel, pksColModel, constModel, err := dialect.Dialect.GetSQLCreateTableColumn(value)
 		if err != nil {
-			continue
+			return "", errors.New("Column " + value.Title + " of table " + table.Name + ": " + err.Error())
 		}
 		columns += columnModel
 		pksFound += pksColModel
 		constraints += constModel
 	}
+	columns += extraColumns
 
 	if len(pksFound) > 0 {
 		pksFound = pksFound[:len(pksFound)-1]
@@ -68,135 +121,759 @@ func (dialect *SQLDatabaseAccess) Create(table models.Table) string {
 	}
 
 	lastColumnIndex := len(columns)
-	sqlquery := "CREATE TABLE " + table.Name + " (" + columns[:lastColumnIndex-1] + constraints + ")"
-	return sqlquery
+	sqlquery := "CREATE TABLE " + dialect.Dialect.QuoteIdentifier(table.Name) + " (" + columns[:lastColumnIndex-1] + constraints + ")" + dialect.Dialect.GetSQLCreateTableOptions()
+	return sqlquery, nil
+}
+
+//Schema reads the structure of an existing table from the database, the schema has no columns if the table does not exist
+func (dialect *SQLDatabaseAccess) Schema(ctx context.Context, db sqlx.QueryerContext, tableName string) (models.TableSchema, error) {
+	return dialect.Dialect.GetTableSchema(ctx, db, tableName)
 }
 
-//Insert generates the required sql sentence to insert the instance value
-func (dialect *SQLDatabaseAccess) Insert(table models.Table, instance interface{}) (string, error) {
-	columns, values, err := getColumnsAndValues(table, instance)
+//Tables returns the names of the tables found in the database
+func (dialect *SQLDatabaseAccess) Tables(ctx context.Context, db sqlx.QueryerContext) ([]string, error) {
+	return dialect.Dialect.GetTables(ctx, db)
+}
+
+//Indexes generates the SQL CREATE INDEX of the indexed columns whose index is not found in the schema
+func (dialect *SQLDatabaseAccess) Indexes(table models.Table, schema models.TableSchema) []string {
+	sentences := make([]string, 0)
+	for _, column := range table.Columns {
+		if !column.Index {
+			continue
+		}
+		name := "idx_" + table.Name + "_" + column.Title
+		if containsName(schema.Indexes, name) {
+			continue
+		}
+		sentences = append(sentences, "CREATE INDEX "+dialect.Dialect.QuoteIdentifier(name)+" ON "+dialect.Dialect.QuoteIdentifier(table.Name)+
+			" ("+dialect.Dialect.QuoteIdentifier(column.Title)+")")
+	}
+	return sentences
+}
+
+//Alter generates the sentences to migrate the existing table described by schema to the table model.
+//New columns, unique constraints, foreign keys and indexes are added. If the dialect cannot alter the table,
+//it is copied into a new table created from the model, the columns not found in the model are kept
+func (dialect *SQLDatabaseAccess) Alter(table models.Table, schema models.TableSchema) (migration Migration, err error) {
+	diff, err := dialect.diff(table, schema)
 	if err != nil {
-		return "", err
+		return migration, err
 	}
-	//sql := "INSERT INTO " + table.Name + " (" + columns + ") values(" + values + ")"
-	sql := "INSERT INTO " + table.Name + " ("
+	sentences, ok, err := dialect.Dialect.GetSQLAlterTable(table.Name, diff)
+	if err != nil {
+		return migration, err
+	}
+	if ok {
+		migration.Sentences = append(sentences, dialect.Indexes(table, schema)...)
+		return migration, nil
+	}
+
+	columns := make([]string, 0)
+	for _, column := range table.Columns {
+		if !column.Ignore && containsColumn(schema.Columns, column.Title) {
+			columns = append(columns, dialect.Dialect.QuoteIdentifier(column.Title))
+		}
+	}
+	extraColumns := ""
+	for _, column := range schema.Columns {
+		if findColumn(table, column.Name) {
+			continue
+		}
+		extraColumns += dialect.Dialect.QuoteIdentifier(column.Name) + " " + column.Type
+		if column.Default != "" {
+			extraColumns += " DEFAULT " + column.Default
+		}
+		extraColumns += ","
+		columns = append(columns, dialect.Dialect.QuoteIdentifier(column.Name))
+	}
+
+	rebuilt := table
+	rebuilt.Name = "goedb_rebuild_" + table.Name
+	create, err := dialect.createTable(rebuilt, extraColumns)
+	if err != nil {
+		return migration, err
+	}
+	copiedColumns := strings.Join(columns, ",")
+	migration.Sentences = []string{
+		create,
+		"INSERT INTO " + dialect.Dialect.QuoteIdentifier(rebuilt.Name) + " (" + copiedColumns + ") SELECT " + copiedColumns + " FROM " + dialect.Dialect.QuoteIdentifier(table.Name),
+		dialect.Drop(table.Name, false),
+		"ALTER TABLE " + dialect.Dialect.QuoteIdentifier(rebuilt.Name) + " RENAME TO " + dialect.Dialect.QuoteIdentifier(table.Name),
+	}
+	migration.Sentences = append(migration.Sentences, dialect.Indexes(table, models.TableSchema{})...)
+	migration.Rebuild = true
+	migration.ForeignKeysOff = dialect.Dialect.GetSQLForeignKeys(false)
+	migration.ForeignKeysOn = dialect.Dialect.GetSQLForeignKeys(true)
+	return migration, nil
+}
+
+//diff returns the changes required to migrate the table described by schema to the table model,
+//an error is returned if the type of a column that is not ignored is unknown
+func (dialect *SQLDatabaseAccess) diff(table models.Table, schema models.TableSchema) (diff models.TableDiff, err error) {
+	primaryKeys := make([]string, 0)
+	for _, column := range table.Columns {
+		if column.Ignore {
+			continue
+		}
+		if _, _, _, err := dialect.Dialect.GetSQLCreateTableColumn(column); err != nil {
+			return diff, errors.New("Column " + column.Title + " of table " + table.Name + ": " + err.Error())
+		}
+		if column.PrimaryKey {
+			primaryKeys = append(primaryKeys, column.Title)
+		}
+		if !containsColumn(schema.Columns, column.Title) {
+			diff.Columns = append(diff.Columns, column)
+			continue
+		}
+		if column.Unique && !containsName(schema.Uniques, column.Title) {
+			diff.Uniques = append(diff.Uniques, column)
+		}
+		if column.ForeignKey.IsForeignKey && !containsName(schema.ForeignKeys, column.Title) {
+			diff.ForeignKeys = append(diff.ForeignKeys, column)
+		}
+	}
+	if len(primaryKeys) != len(schema.PrimaryKeys) {
+		diff.PrimaryKeyChanged = true
+	}
+	for _, name := range primaryKeys {
+		if !containsName(schema.PrimaryKeys, name) {
+			diff.PrimaryKeyChanged = true
+		}
+	}
+	return diff, nil
+}
+
+//Insert generates the required sql sentence to insert the instance value. If the dialect supports it, the
+//generated columns are returned by the sentence, in that case returning is true and it must be run as a query
+func (dialect *SQLDatabaseAccess) Insert(table models.Table, instance interface{}) (sql string, args []interface{}, returning bool, err error) {
+	sql, _, args, err = dialect.insert(table, instance)
+	if err != nil {
+		return "", nil, false, err
+	}
+	sql, returning = dialect.returning(sql, table, instance)
+	return sql, args, returning, nil
+}
+
+//ConflictColumns returns the columns used to find the row updated by an upsert. The columns received must be primary keys
+//or unique columns with a value in the instance. If there are not any, the primary keys are used or, if they are generated
+//by the database, the first unique column with a value. The autoincrement primary keys with a value are inserted by the upsert,
+//so they can be used too
+func (dialect *SQLDatabaseAccess) ConflictColumns(table models.Table, instance interface{}, columns []string) ([]string, error) {
+	inserted, _, err := getUpsertColumnsAndValues(table, instance)
+	if err != nil {
+		return nil, err
+	}
+	if len(columns) == 0 {
+		for _, column := range table.Columns {
+			if column.PrimaryKey {
+				columns = append(columns, column.Title)
+			}
+		}
+		for _, name := range columns {
+			if !containsName(inserted, name) {
+				columns = nil
+				break
+			}
+		}
+		for _, column := range table.Columns {
+			if len(columns) == 0 && column.Unique && containsName(inserted, column.Title) {
+				columns = []string{column.Title}
+			}
+		}
+		if len(columns) == 0 {
+			return nil, errors.New("No primary key or unique column with a value found in table " + table.Name)
+		}
+		return columns, nil
+	}
+	for _, name := range columns {
+		column, ok := getColumn(table, name)
+		if !ok || (!column.PrimaryKey && !column.Unique) {
+			return nil, errors.New("Column " + name + " is not a primary key or unique column of table " + table.Name)
+		}
+		if !containsName(inserted, name) {
+			return nil, errors.New("Column " + name + " has not got a value, it cannot be used to find the conflicts")
+		}
+	}
+	return columns, nil
+}
+
+//Upsert generates the sentence to insert the instance value or, if a row with the same conflict columns exists,
+//to update its columns except the primary keys. As in Insert, returning is true when the sentence returns the generated columns
+func (dialect *SQLDatabaseAccess) Upsert(table models.Table, instance interface{}, conflictColumns []string) (sql string, args []interface{}, returning bool, err error) {
+	columns, args, err := getUpsertColumnsAndValues(table, instance)
+	if err != nil {
+		return "", nil, false, err
+	}
+	sql = dialect.insertSQL(table.Name, columns, 1)
+	updateColumns := make([]string, 0, len(columns))
+	for _, name := range columns {
+		if column, _ := getColumn(table, name); !column.PrimaryKey && !column.CreatedAt && !column.SoftDelete && !containsName(conflictColumns, name) {
+			updateColumns = append(updateColumns, name)
+		}
+	}
+	sql += dialect.Dialect.GetSQLUpsert(conflictColumns, updateColumns)
+	sql, returning = dialect.returning(sql, table, instance)
+	return sql, args, returning, nil
+}
+
+//UpsertedRow returns the sentence to read the generated columns of the row inserted or updated by an upsert
+func (dialect *SQLDatabaseAccess) UpsertedRow(table models.Table, generated []models.Column, conflictColumns []string, instance interface{}) (string, []interface{}, error) {
+	columns, values, err := getUpsertColumnsAndValues(table, instance)
+	if err != nil {
+		return "", nil, err
+	}
+	sql := "SELECT "
+	for _, column := range generated {
+		sql += dialect.Dialect.QuoteIdentifier(column.Title) + ","
+	}
+	sql = sql[:len(sql)-1] + " FROM " + dialect.Dialect.QuoteIdentifier(table.Name) + " WHERE "
+	args := make([]interface{}, 0, len(conflictColumns))
+	for i, name := range conflictColumns {
+		if i > 0 {
+			sql += " AND "
+		}
+		sql += dialect.Dialect.QuoteIdentifier(name) + " = ?"
+		for j, column := range columns {
+			if strings.EqualFold(column, name) {
+				args = append(args, values[j])
+			}
+		}
+	}
+	return sql, args, nil
+}
+
+//InsertAll generates the multi-row INSERT sentences of the instances. A new sentence is started when the bindvars
+//of the dialect are exhausted or when the columns inserted change, they depend on the generated columns of each instance
+func (dialect *SQLDatabaseAccess) InsertAll(table models.Table, instances []interface{}) ([]Batch, error) {
+	batches := make([]Batch, 0)
+	var batch Batch
+	var batchColumns []string
+	for _, instance := range instances {
+		columns, values, err := getColumnsAndValues(table, instance, true)
+		if err != nil {
+			return nil, err
+		}
+		maxRows := 1
+		if len(columns) > 0 {
+			maxRows = dialect.Dialect.GetMaxParameters() / len(columns)
+		}
+		if len(batch.Instances) > 0 && (len(batch.Instances) >= maxRows || strings.Join(columns, ",") != strings.Join(batchColumns, ",")) {
+			batches = append(batches, dialect.batch(table, batch, batchColumns))
+			batch = Batch{}
+		}
+		batchColumns = columns
+		batch.Instances = append(batch.Instances, instance)
+		batch.Args = append(batch.Args, values...)
+	}
+	if len(batch.Instances) > 0 {
+		batches = append(batches, dialect.batch(table, batch, batchColumns))
+	}
+	return batches, nil
+}
+
+//InsertedIDs returns the autoincrement values of the rows inserted by a batch, ok is false if the dialect cannot know them
+func (dialect *SQLDatabaseAccess) InsertedIDs(lastInsertID int64, rows int) ([]int64, bool) {
+	return dialect.Dialect.GetInsertedIDs(lastInsertID, rows)
+}
+
+//batch sets the sentence of a batch with the columns inserted
+func (dialect *SQLDatabaseAccess) batch(table models.Table, batch Batch, columns []string) Batch {
+	batch.SQL, batch.Returning = dialect.returning(dialect.insertSQL(table.Name, columns, len(batch.Instances)), table, batch.Instances[0])
+	return batch
+}
+
+//insert generates the INSERT sentence of the instance value, the generated columns are not inserted
+func (dialect *SQLDatabaseAccess) insert(table models.Table, instance interface{}) (string, []string, []interface{}, error) {
+	columns, values, err := getColumnsAndValues(table, instance, true)
+	if err != nil {
+		return "", nil, nil, err
+	}
+	return dialect.insertSQL(table.Name, columns, 1), columns, values, nil
+}
+
+//insertSQL generates the INSERT sentence of a number of rows with the columns received
+func (dialect *SQLDatabaseAccess) insertSQL(tableName string, columns []string, rows int) string {
+	sql := "INSERT INTO " + dialect.Dialect.QuoteIdentifier(tableName) + " ("
 	for _, column := range columns {
-		sql += column + ","
+		sql += dialect.Dialect.QuoteIdentifier(column) + ","
 	}
 	sql = sql[:len(sql)-1]
-	sql += ") values("
-	for _, value := range values {
-		sql += value + ","
+	sql += ") values"
+	row := "(" + strings.TrimSuffix(strings.Repeat("?,", len(columns)), ",") + ")"
+	for i := 0; i < rows; i++ {
+		if i > 0 {
+			sql += ","
+		}
+		sql += row
 	}
-	sql = sql[:len(sql)-1]
-	sql += ")"
-	return sql, nil
+	return sql
+}
+
+//returning adds the clause to return the generated columns of the instance, if the dialect supports it
+func (dialect *SQLDatabaseAccess) returning(sql string, table models.Table, instance interface{}) (string, bool) {
+	generated := dialect.Generated(table, instance)
+	if len(generated) > 0 {
+		if returningClause := dialect.Dialect.GetSQLReturning(getColumnTitles(generated)); returningClause != "" {
+			return sql + returningClause, true
+		}
+	}
+	return sql, false
+}
+
+//Generated returns the columns whose values will be generated by the database when the instance is inserted
+func (dialect *SQLDatabaseAccess) Generated(table models.Table, instance interface{}) []models.Column {
+	intanceValue := models.GetValue(instance)
+	generated := make([]models.Column, 0)
+	for i, column := range table.Columns {
+		if column.Ignore {
+			continue
+		}
+		if models.IsGenerated(column, intanceValue.Field(i)) {
+			generated = append(generated, column)
+		}
+	}
+	return generated
+}
+
+//InsertedRow returns the sentence to read the generated columns of the row inserted. The row is found using the
+//primary keys of the instance or, if they are generated, the last insert id: the value of the autoincrement primary
+//key or of the row id of the dialect. It returns false if the row cannot be found, its primary keys are generated
+//without autoincrement and the dialect has not got a row id
+func (dialect *SQLDatabaseAccess) InsertedRow(table models.Table, generated []models.Column, instance interface{}, lastInsertID int64) (string, []interface{}, bool) {
+	sql := "SELECT "
+	for _, column := range generated {
+		sql += dialect.Dialect.QuoteIdentifier(column.Title) + ","
+	}
+	sql = sql[:len(sql)-1] + " FROM " + dialect.Dialect.QuoteIdentifier(table.Name) + " WHERE "
+
+	generatedKey := false
+	for _, column := range generated {
+		generatedKey = generatedKey || column.PrimaryKey
+	}
+	if pkc, pkv, err := getPrimaryKeysAndValues(table, instance); err == nil && !generatedKey {
+		for i, column := range pkc {
+			if i > 0 {
+				sql += " AND "
+			}
+			sql += dialect.Dialect.QuoteIdentifier(column) + " = ?"
+		}
+		return sql, pkv, true
+	}
+
+	condition := dialect.Dialect.GetSQLRowID()
+	for _, column := range table.Columns {
+		if column.PrimaryKey && column.AutoIncrement {
+			condition = dialect.Dialect.QuoteIdentifier(column.Title)
+		}
+	}
+	if condition == "" {
+		return "", nil, false
+	}
+	return sql + condition + " = ?", []interface{}{lastInsertID}, true
 }
 
 //First returns the TransientSQL sentence depending on the table and the instance
-func (dialect *SQLDatabaseAccess) First(table models.Table, where string, instance interface{}) (string, error) {
-	sql, relationContraints, err := generateSQLQuery(table, dialect.Models)
+func (dialect *SQLDatabaseAccess) First(table models.Table, where string, params map[string]interface{}, instance interface{}) (string, []interface{}, error) {
+	sql, relationContraints, filters, err := generateSQLQuery(table, dialect.Models, dialect.Dialect, dialect.withDeleted)
 
 	if err != nil {
-		return "", err
+		return "", nil, err
 	}
 
+	var args []interface{}
 	if where == "" {
 		pkc, pkv, err := getPrimaryKeysAndValues(table, instance)
 		if err != nil {
-			return "", err
+			return "", nil, err
 		}
-		//sql += " WHERE " + table.Name + "." + pkc + "=" + pkv
 		if len(pkc) > 0 {
-			sql += " WHERE " + table.Name + "." + pkc[0] + "=" + pkv[0]
+			sql += " WHERE " + dialect.quoteColumn(table.Name, pkc[0]) + "=?"
 			for i := 1; i < len(pkc); i++ {
-				sql += " AND " + table.Name + "." + pkc[i] + "=" + pkv[i]
+				sql += " AND " + dialect.quoteColumn(table.Name, pkc[i]) + "=?"
 			}
 		}
+		args = pkv
 	} else {
-		sql += " WHERE " + where
+		where, args, err = bindWhere(where, params)
+		if err != nil {
+			return "", nil, err
+		}
+		sql += " WHERE " + scopeWhere(where, filters)
 	}
 	//contraints are generated by relations between objects
-	sql += relationContraints
-	return sql, nil
+	sql += relationContraints + filters
+	return sql, args, nil
 }
 
 //Find returns the TransientSQL sentence depending on the table and the instance
-func (dialect *SQLDatabaseAccess) Find(table models.Table, where string, instance interface{}) (string, error) {
+func (dialect *SQLDatabaseAccess) Find(table models.Table, where string, params map[string]interface{}, instance interface{}) (string, []interface{}, error) {
+	return dialect.Select(table, Query{Where: where, Params: params})
+}
+
+//Select returns the sentence to get the rows of a table (and its relations) matching the query
+func (dialect *SQLDatabaseAccess) Select(table models.Table, query Query) (string, []interface{}, error) {
 	//SQL generated by entity
-	sql, relationContraints, err := generateSQLQuery(table, dialect.Models)
+	sql, relationContraints, filters, err := generateSQLQuery(table, dialect.Models, dialect.Dialect, dialect.withDeleted || query.WithDeleted)
 
 	if err != nil {
-		return "", err
+		return "", nil, err
 	}
+	where := scopeWhere(query.Where, filters)
+	relationContraints += filters
 
-	if where == "" && len(relationContraints) > 5 {
+	var args []interface{}
+	if query.Where == "" && len(relationContraints) > 5 {
 		//contraints are generated by relations between objects
 		sql += " WHERE " + relationContraints[5:]
-	} else if where != "" {
+	} else if query.Where != "" {
 		//where clause
+		where, args, err = bindWhere(where, query.Params)
+		if err != nil {
+			return "", nil, err
+		}
 		sql += " WHERE " + where
 		sql += relationContraints
 	}
 
-	return sql, nil
+	if query.OrderBy != "" {
+		sql += " ORDER BY " + query.OrderBy
+	}
+	if query.Limit > 0 || query.Offset > 0 {
+		sql += dialect.Dialect.GetSQLLimitOffset(query.Limit, query.Offset)
+	}
+	return sql, args, nil
+}
+
+//JSONPath returns the expression to get the value of a path from a JSON column to compare it with value,
+//the elements of the path can only contain letters, digits and underscores
+func (dialect *SQLDatabaseAccess) JSONPath(column string, path []string, value interface{}) (string, error) {
+	for _, element := range path {
+		if !jsonPathElement.MatchString(element) {
+			return "", errors.New("Invalid JSON path element \"" + element + "\"")
+		}
+	}
+	return dialect.Dialect.GetSQLJSONPath(column, path, value), nil
+}
+
+//Update returns the TransientSQL sentence depending on the table and the instance. If the table has a version column,
+//the sentence increments it and only updates the row if it still has the version of the instance
+func (dialect *SQLDatabaseAccess) Update(table models.Table, instance interface{}) (string, []interface{}, error) {
+	columns, values, err := getColumnsAndValues(table, instance, false)
+	if err != nil {
+		return "", nil, err
+	}
+	return dialect.update(table, instance, columns, values)
 }
 
-//Update returns the TransientSQL sentence depending on the table and the instance
-func (dialect *SQLDatabaseAccess) Update(table models.Table, instance interface{}) (string, error) {
-	columns, values, err := getColumnsAndValues(table, instance)
+//UpdateColumns returns the sentence updating only the columns given (names of columns or of their fields) of the row of the instance,
+//the primary keys cannot be updated
+func (dialect *SQLDatabaseAccess) UpdateColumns(table models.Table, instance interface{}, columns []string) (string, []interface{}, error) {
+	if len(columns) == 0 {
+		return "", nil, errors.New("No columns to update in table " + table.Name)
+	}
+	selected := make([]string, 0, len(columns))
+	for _, name := range columns {
+		column, ok := getColumnOrField(table, name)
+		if !ok || column.Ignore {
+			return "", nil, errors.New("Column " + name + " not found in table " + table.Name)
+		}
+		if column.PrimaryKey || column.AutoIncrement || column.Version || column.CreatedAt || column.UpdatedAt || column.SoftDelete {
+			return "", nil, errors.New("Column " + name + " of table " + table.Name + " cannot be updated")
+		}
+		selected = append(selected, column.Title)
+	}
+	allColumns, allValues, err := getColumnsAndValues(table, instance, false)
+	if err != nil {
+		return "", nil, err
+	}
+	updateColumns := make([]string, 0, len(selected))
+	updateValues := make([]interface{}, 0, len(selected))
+	for i, column := range allColumns {
+		if containsName(selected, column) {
+			updateColumns = append(updateColumns, column)
+			updateValues = append(updateValues, allValues[i])
+		}
+	}
+	return dialect.update(table, instance, updateColumns, updateValues)
+}
+
+//Snapshot returns the key of the row of the instance and the values of its updatable columns, they are compared with
+//the current ones to find the columns changed. An error is returned if the table has no primary key
+func (dialect *SQLDatabaseAccess) Snapshot(table models.Table, instance interface{}) (string, map[string]interface{}, error) {
+	_, pkv, err := getPrimaryKeysAndValues(table, instance)
+	if err != nil {
+		return "", nil, err
+	}
+	columns, values, err := getColumnsAndValues(table, instance, false)
 	if err != nil {
-		return "", err
+		return "", nil, err
 	}
-	sql := "UPDATE " + table.Name + " SET "
-	for i, column := range columns {
-		sql += column + " = " + values[i] + ","
+	snapshot := make(map[string]interface{}, len(columns))
+	for i, name := range columns {
+		if column, _ := getColumn(table, name); column.Version || column.CreatedAt || column.UpdatedAt || column.SoftDelete {
+			continue
+		}
+		if valuer, ok := values[i].(driver.Valuer); ok {
+			if values[i], err = valuer.Value(); err != nil {
+				return "", nil, err
+			}
+		}
+		snapshot[name] = snapshotValue(reflect.ValueOf(values[i]))
+	}
+	return fmt.Sprintf("%#v", pkv), snapshot, nil
+}
+
+//snapshotValue returns a copy of a value that does not share memory with the entity, the pointers are dereferenced
+//and the slices and maps are copied, so the changes made in place are found
+func snapshotValue(value reflect.Value) interface{} {
+	switch value.Kind() {
+	case reflect.Invalid:
+		return nil
+	case reflect.Ptr:
+		if value.IsNil() {
+			return nil
+		}
+		return snapshotValue(value.Elem())
+	case reflect.Slice:
+		if value.IsNil() {
+			return nil
+		}
+		copied := make([]interface{}, value.Len())
+		for i := range copied {
+			copied[i] = snapshotValue(value.Index(i))
+		}
+		return copied
+	case reflect.Map:
+		if value.IsNil() {
+			return nil
+		}
+		copied := make(map[interface{}]interface{}, value.Len())
+		for _, key := range value.MapKeys() {
+			copied[key.Interface()] = snapshotValue(value.MapIndex(key))
+		}
+		return copied
+	}
+	return value.Interface()
+}
+
+func (dialect *SQLDatabaseAccess) update(table models.Table, instance interface{}, columns []string, values []interface{}) (string, []interface{}, error) {
+	version, versioned := models.GetVersionColumn(table)
+	var currentVersion int64
+	args := make([]interface{}, 0, len(values)+1)
+	sql := "UPDATE " + dialect.Dialect.QuoteIdentifier(table.Name) + " SET "
+	for i, name := range columns {
+		if column, _ := getColumn(table, name); column.Version || column.CreatedAt || column.UpdatedAt || column.SoftDelete {
+			continue
+		}
+		sql += dialect.Dialect.QuoteIdentifier(name) + " = ?,"
+		args = append(args, values[i])
+	}
+	for _, column := range table.Columns {
+		if column.UpdatedAt && !column.Ignore {
+			sql += dialect.Dialect.QuoteIdentifier(column.Title) + " = ?,"
+			args = append(args, models.ColumnValue(column, models.GetValue(instance).FieldByName(column.Field)))
+		}
+	}
+	if versioned {
+		var err error
+		if currentVersion, err = models.VersionValue(version, models.GetValue(instance).FieldByName(version.Field)); err != nil {
+			return "", nil, err
+		}
+		sql += dialect.Dialect.QuoteIdentifier(version.Title) + " = ?,"
+		args = append(args, currentVersion+1)
 	}
 	sql = sql[:len(sql)-1]
 	pkc, pkv, err := getPrimaryKeysAndValues(table, instance)
 	if err != nil {
-		return "", errors.New("Error getting primary key")
+		return "", nil, errors.New("Error getting primary key")
 	}
-	//sql += " WHERE " + table.Name + "." + pkc + "=" + pkv
 	if len(pkc) > 0 {
-		sql += " WHERE " + table.Name + "." + pkc[0] + "=" + pkv[0]
+		sql += " WHERE " + dialect.quoteColumn(table.Name, pkc[0]) + "=?"
 		for i := 1; i < len(pkc); i++ {
-			sql += " AND " + table.Name + "." + pkc[i] + "=" + pkv[i]
+			sql += " AND " + dialect.quoteColumn(table.Name, pkc[i]) + "=?"
 		}
 	}
-	return sql, nil
+	args = append(args, pkv...)
+	if versioned {
+		sql += " AND " + dialect.quoteColumn(table.Name, version.Title) + "=?"
+		args = append(args, currentVersion)
+	}
+	return sql, args, nil
+}
+
+//UpdateWhere returns the sentence updating the columns of every row matching the where clause. The keys of values are
+//the names of the columns (or of their fields) and they are set in alphabetical order, foreign keys take the value of the referenced column.
+//The version column, if the table has one, is incremented unless it is set in values
+func (dialect *SQLDatabaseAccess) UpdateWhere(table models.Table, values map[string]interface{}, where string, params map[string]interface{}) (string, []interface{}, error) {
+	if len(values) == 0 {
+		return "", nil, errors.New("No columns to update in table " + table.Name)
+	}
+	if where == "" {
+		return "", nil, errors.New("UpdateWhere requires a where clause")
+	}
+	names := make([]string, 0, len(values))
+	for name := range values {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	assignments := make([]string, 0, len(names))
+	args := make([]interface{}, 0, len(names))
+	versionSet := false
+	for _, name := range names {
+		column, ok := getColumnOrField(table, name)
+		if !ok || column.Ignore {
+			return "", nil, errors.New("Column " + name + " not found in table " + table.Name)
+		}
+		versionSet = versionSet || column.Version
+		assignments = append(assignments, dialect.Dialect.QuoteIdentifier(column.Title)+" = ?")
+		args = append(args, columnValue(column, values[name]))
+	}
+	if version, ok := models.GetVersionColumn(table); ok && !versionSet {
+		quoted := dialect.Dialect.QuoteIdentifier(version.Title)
+		assignments = append(assignments, quoted+" = "+quoted+" + 1")
+	}
+	where, whereArgs, err := bindWhere(where, params)
+	if err != nil {
+		return "", nil, err
+	}
+	sql := "UPDATE " + dialect.Dialect.QuoteIdentifier(table.Name) + " SET " + strings.Join(assignments, ",") + " WHERE " + where
+	return sql, append(args, whereArgs...), nil
+}
+
+//DeleteWhere returns the sentence deleting every row matching the where clause
+func (dialect *SQLDatabaseAccess) DeleteWhere(table models.Table, where string, params map[string]interface{}) (string, []interface{}, error) {
+	if where == "" {
+		return "", nil, errors.New("DeleteWhere requires a where clause")
+	}
+	return dialect.Delete(table, where, params, nil)
 }
 
 //Delete returns the TransientSQL sentence depending on the table and the instance
-func (dialect *SQLDatabaseAccess) Delete(table models.Table, where string, instance interface{}) (string, error) {
-	sql := "DELETE FROM " + table.Name + " WHERE "
+func (dialect *SQLDatabaseAccess) Delete(table models.Table, where string, params map[string]interface{}, instance interface{}) (string, []interface{}, error) {
+	sql := "DELETE FROM " + dialect.Dialect.QuoteIdentifier(table.Name) + " WHERE "
 	if where == "" {
 		pkc, pkv, err := getPrimaryKeysAndValues(table, instance)
 		if err != nil {
-			return "", err
+			return "", nil, err
 		}
-		//sql += pkc + "=" + pkv
 		if len(pkc) > 0 {
-			sql += pkc[0] + "=" + pkv[0]
+			sql += dialect.Dialect.QuoteIdentifier(pkc[0]) + "=?"
 			for i := 1; i < len(pkc); i++ {
-				sql += " AND " + pkc[i] + "=" + pkv[i]
+				sql += " AND " + dialect.Dialect.QuoteIdentifier(pkc[i]) + "=?"
 			}
 		}
+		return sql, pkv, nil
+	}
+	where, args, err := bindWhere(where, params)
+	if err != nil {
+		return "", nil, err
+	}
+	return sql + where, args, nil
+}
+
+//SoftDelete returns the sentence marking as removed the row of the instance, or the rows matching the where clause if it is not empty.
+//The softDelete column is set to deletedAt, or to true if it is a boolean, in the rows not removed yet
+func (dialect *SQLDatabaseAccess) SoftDelete(table models.Table, where string, params map[string]interface{}, instance interface{}, deletedAt time.Time) (string, []interface{}, error) {
+	column, ok := models.GetSoftDeleteColumn(table)
+	if !ok {
+		return "", nil, errors.New("Table " + table.Name + " has no softDelete column")
+	}
+	sql := "UPDATE " + dialect.Dialect.QuoteIdentifier(table.Name) + " SET " + dialect.Dialect.QuoteIdentifier(column.Title)
+	var args []interface{}
+	if column.ColumnType == reflect.Bool {
+		sql += " = " + dialect.Dialect.GetSQLBoolean(true)
 	} else {
-		sql += where
+		sql += " = ?"
+		args = append(args, models.DatabaseValue(deletedAt))
+	}
+	where, whereArgs, err := dialect.rowsWhere(table, where, params, instance)
+	if err != nil {
+		return "", nil, err
 	}
-	return sql, nil
+	filter := softDeleteFilter(table, dialect.Dialect)
+	return sql + " WHERE " + scopeWhere(where, filter) + filter, append(args, whereArgs...), nil
 }
 
-//Drop returns the TransientSQL sentence depending on the table and the instance
-func (dialect *SQLDatabaseAccess) Drop(tableName string) string {
-	return "DROP TABLE " + tableName
+//Restore returns the sentence unmarking as removed the row of the instance, or the rows matching the where clause if it is not empty
+func (dialect *SQLDatabaseAccess) Restore(table models.Table, where string, params map[string]interface{}, instance interface{}) (string, []interface{}, error) {
+	column, ok := models.GetSoftDeleteColumn(table)
+	if !ok {
+		return "", nil, errors.New("Table " + table.Name + " has no softDelete column")
+	}
+	sql := "UPDATE " + dialect.Dialect.QuoteIdentifier(table.Name) + " SET " + dialect.Dialect.QuoteIdentifier(column.Title)
+	if column.ColumnType == reflect.Bool {
+		sql += " = " + dialect.Dialect.GetSQLBoolean(false)
+	} else {
+		sql += " = NULL"
+	}
+	where, args, err := dialect.rowsWhere(table, where, params, instance)
+	if err != nil {
+		return "", nil, err
+	}
+	return sql + " WHERE " + where, args, nil
 }
 
-func generateSQLQuery(table models.Table, modelMap map[string]models.Table) (query string, constraints string, err error) {
+//rowsWhere returns the where clause with its parameters bound or, if it is empty, the condition matching the primary keys of the instance
+func (dialect *SQLDatabaseAccess) rowsWhere(table models.Table, where string, params map[string]interface{}, instance interface{}) (string, []interface{}, error) {
+	if where != "" {
+		return bindWhere(where, params)
+	}
+	pkc, pkv, err := getPrimaryKeysAndValues(table, instance)
+	if err != nil {
+		return "", nil, err
+	}
+	conditions := make([]string, 0, len(pkc))
+	for _, name := range pkc {
+		conditions = append(conditions, dialect.quoteColumn(table.Name, name)+"=?")
+	}
+	return strings.Join(conditions, " AND "), pkv, nil
+}
+
+//Drop returns the sentence to drop a table, if ifExists is true the sentence does not fail when the table does not exist
+func (dialect *SQLDatabaseAccess) Drop(tableName string, ifExists bool) string {
+	return dialect.Dialect.GetSQLDropTable(tableName, ifExists)
+}
+
+//Rebind replaces the "?" bindvars of a sentence with the placeholders of the dialect,
+//the question marks found in quoted strings and identifiers are kept
+func (dialect *SQLDatabaseAccess) Rebind(sql string) string {
+	var rebound strings.Builder
+	var quote rune
+	index := 0
+	for _, r := range sql {
+		switch {
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			}
+		case r == '\'' || r == '"' || r == '`':
+			quote = r
+		case r == '?':
+			index++
+			rebound.WriteString(dialect.Dialect.GetPlaceholder(index))
+			continue
+		}
+		rebound.WriteRune(r)
+	}
+	return rebound.String()
+}
+
+//quoteColumn returns the column qualified by its table, both quoted
+func (dialect *SQLDatabaseAccess) quoteColumn(tableName string, columnName string) string {
+	return dialect.Dialect.QuoteIdentifier(tableName) + "." + dialect.Dialect.QuoteIdentifier(columnName)
+}
+
+// generateSQLQuery returns the SELECT of a table and its relations, the constraints joining them and, unless withDeleted
+// is true, the filters excluding the rows soft deleted of every table selected
+func generateSQLQuery(table models.Table, modelMap map[string]models.Table, specifics dialect.Dialect, withDeleted bool) (query string, constraints string, filters string, err error) {
 	query = "SELECT "
-	from := " FROM " + table.Name + ","
+	from := " FROM " + specifics.QuoteIdentifier(table.Name) + ","
 	constraints = ""
+	if !withDeleted {
+		filters = softDeleteFilter(table, specifics)
+	}
 
 	for _, column := range table.Columns {
 
@@ -205,10 +882,10 @@ func generateSQLQuery(table models.Table, modelMap map[string]models.Table) (que
 		}
 
 		if !column.IsComplex {
-			query += table.Name + "." + column.Title + ","
+			query += specifics.QuoteIdentifier(table.Name) + "." + specifics.QuoteIdentifier(column.Title) + ","
 			continue
 		}
-		referencedTable, ok := modelMap[column.ColumnTypeName]
+		referencedTable, ok := modelMap[column.ForeignKey.ForeignKeyModel]
 
 		if !ok {
 			err = errors.New("Model " + column.ColumnTypeName + " NOT FOUND")
@@ -216,18 +893,22 @@ func generateSQLQuery(table models.Table, modelMap map[string]models.Table) (que
 
 		for _, primaryKey := range referencedTable.PrimaryKeys {
 			if primaryKey.Name == column.ForeignKey.ForeignKeyColumnReference {
-				constraints += " AND " + table.Name + "." + column.Title + " = " + referencedTable.Name + "." + primaryKey.Name
-				err = referenceSQLEntity(&from, &query, &constraints, referencedTable, modelMap)
+				constraints += " AND " + specifics.QuoteIdentifier(table.Name) + "." + specifics.QuoteIdentifier(column.Title) +
+					" = " + specifics.QuoteIdentifier(referencedTable.Name) + "." + specifics.QuoteIdentifier(primaryKey.Name)
+				err = referenceSQLEntity(&from, &query, &constraints, &filters, referencedTable, modelMap, specifics, withDeleted)
 			}
 		}
 	}
 	//Removing last ','
 	query = query[:len(query)-1] + from[:len(from)-1]
-	return query, constraints, err
+	return query, constraints, filters, err
 }
 
-func referenceSQLEntity(from *string, query *string, constraints *string, table models.Table, modelMap map[string]models.Table) (err error) {
-	*from += table.Name + ","
+func referenceSQLEntity(from *string, query *string, constraints *string, filters *string, table models.Table, modelMap map[string]models.Table, specifics dialect.Dialect, withDeleted bool) (err error) {
+	*from += specifics.QuoteIdentifier(table.Name) + ","
+	if !withDeleted {
+		*filters += softDeleteFilter(table, specifics)
+	}
 	for _, column := range table.Columns {
 
 		if column.Ignore {
@@ -235,11 +916,11 @@ func referenceSQLEntity(from *string, query *string, constraints *string, table
 		}
 
 		if !column.IsComplex {
-			*query += table.Name + "." + column.Title + ","
+			*query += specifics.QuoteIdentifier(table.Name) + "." + specifics.QuoteIdentifier(column.Title) + ","
 			continue
 		}
 
-		referencedTable, ok := modelMap[column.ColumnTypeName]
+		referencedTable, ok := modelMap[column.ForeignKey.ForeignKeyModel]
 
 		if !ok {
 			return errors.New("Model " + column.ColumnTypeName + " NOT FOUND")
@@ -247,15 +928,48 @@ func referenceSQLEntity(from *string, query *string, constraints *string, table
 
 		for _, primaryKey := range referencedTable.PrimaryKeys {
 			if primaryKey.Name == column.ForeignKey.ForeignKeyColumnReference {
-				*constraints += " AND " + table.Name + "." + column.Title + " = " + referencedTable.Name + "." + primaryKey.Name
-				referenceSQLEntity(from, query, constraints, modelMap[column.ColumnTypeName], modelMap)
+				*constraints += " AND " + specifics.QuoteIdentifier(table.Name) + "." + specifics.QuoteIdentifier(column.Title) +
+					" = " + specifics.QuoteIdentifier(referencedTable.Name) + "." + specifics.QuoteIdentifier(primaryKey.Name)
+				referenceSQLEntity(from, query, constraints, filters, referencedTable, modelMap, specifics, withDeleted)
 			}
 		}
 	}
 	return err
 }
 
-func getPrimaryKeysAndValues(gt models.Table, obj interface{}) (columnName []string, columnValue []string, err error) {
+// scopeWhere encloses a where clause in parentheses when the soft delete filters are added to it, so they apply to all its conditions
+func scopeWhere(where string, filters string) string {
+	if where == "" || filters == "" {
+		return where
+	}
+	return "(" + where + ")"
+}
+
+// softDeleteFilter returns the condition excluding the rows soft deleted of a table, an empty string if the table has no
+// softDelete column. Timestamps are NULL until the row is removed and booleans are NULL or false
+func softDeleteFilter(table models.Table, specifics dialect.Dialect) string {
+	column, ok := models.GetSoftDeleteColumn(table)
+	if !ok {
+		return ""
+	}
+	quoted := specifics.QuoteIdentifier(table.Name) + "." + specifics.QuoteIdentifier(column.Title)
+	if column.ColumnType == reflect.Bool {
+		return " AND (" + quoted + " IS NULL OR " + quoted + " = " + specifics.GetSQLBoolean(false) + ")"
+	}
+	return " AND " + quoted + " IS NULL"
+}
+
+// bindWhere replaces the named parameters (:name) of a where clause with bindvars,
+// returning the values in the same order they appear in the clause
+func bindWhere(where string, params map[string]interface{}) (string, []interface{}, error) {
+	values := make(map[string]interface{}, len(params))
+	for name, value := range params {
+		values[name] = models.DatabaseValue(value)
+	}
+	return sqlx.Named(where, values)
+}
+
+func getPrimaryKeysAndValues(gt models.Table, obj interface{}) (columnName []string, columnValue []interface{}, err error) {
 	err = errors.New("No primary key found")
 	val := reflect.ValueOf(obj)
 
@@ -272,52 +986,37 @@ func getPrimaryKeysAndValues(gt models.Table, obj interface{}) (columnName []str
 			if columnToAnalize.IsComplex {
 				columnValue = append(columnValue, getRelationPrimaryKeyValue(columnToAnalize, v))
 			} else {
-				columnValue = append(columnValue, getPrimaryKeyValue(columnToAnalize, v))
+				columnValue = append(columnValue, models.FieldValue(v))
 			}
 		}
 	}
 	return columnName, columnValue, err
 }
 
-func getPrimaryKeyValue(columnToAnalize models.Column, v reflect.Value) (columnValue string) {
-	switch columnToAnalize.ColumnType {
-	case reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int, reflect.Int64, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint, reflect.Uint64:
-		columnValue = strconv.FormatInt(v.Int(), 10)
-	case reflect.Float32, reflect.Float64:
-		columnValue = strconv.FormatFloat(v.Float(), 'f', 6, 64)
-	case reflect.Bool:
-		if v.Bool() {
-			columnValue = "1"
-		} else {
-			columnValue = "0"
-		}
-	case reflect.String:
-		columnValue = "'" + v.String() + "'"
-	}
-	return columnValue
+func getRelationPrimaryKeyValue(fkColumn models.Column, v reflect.Value) interface{} {
+	return v.FieldByName(fkColumn.ForeignKey.ForeignKeyField).Interface()
 }
 
-func getRelationPrimaryKeyValue(fkColumn models.Column, v reflect.Value) (columnValue string) {
-	referencedFKColumn := v.FieldByName(fkColumn.ForeignKey.ForeignKeyColumnReference)
-
-	switch fkColumn.ColumnType {
-	case reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int, reflect.Int64, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint, reflect.Uint64:
-		columnValue = strconv.FormatInt(referencedFKColumn.Int(), 10)
-	case reflect.Float32, reflect.Float64:
-		columnValue = strconv.FormatFloat(referencedFKColumn.Float(), 'f', 6, 64)
-	case reflect.Bool:
-		if v.Bool() {
-			columnValue = "1"
-		} else {
-			columnValue = "0"
+//getUpsertColumnsAndValues returns the columns inserted by an upsert, unlike an insert the autoincrement primary keys
+//with a value are included to find the row already stored
+func getUpsertColumnsAndValues(table models.Table, instance interface{}) ([]string, []interface{}, error) {
+	columns, values, err := getColumnsAndValues(table, instance, true)
+	if err != nil {
+		return nil, nil, err
+	}
+	intanceValue := models.GetValue(instance)
+	keys := make([]string, 0)
+	keyValues := make([]interface{}, 0)
+	for i, column := range table.Columns {
+		if column.PrimaryKey && column.AutoIncrement && !column.Ignore && !intanceValue.Field(i).IsZero() {
+			keys = append(keys, column.Title)
+			keyValues = append(keyValues, models.ColumnValue(column, intanceValue.Field(i)))
 		}
-	case reflect.String:
-		columnValue = "'" + referencedFKColumn.String() + "'"
 	}
-	return columnValue
+	return append(keys, columns...), append(keyValues, values...), nil
 }
 
-func getColumnsAndValues(table models.Table, instance interface{}) (columns []string, values []string, err error) {
+func getColumnsAndValues(table models.Table, instance interface{}, insert bool) (columns []string, values []interface{}, err error) {
 	instanceType := models.GetType(instance)
 	intanceValue := models.GetValue(instance)
 
@@ -332,8 +1031,17 @@ func getColumnsAndValues(table models.Table, instance interface{}) (columns []st
 			continue
 		}
 
+		//On insert, the database will set the default values of the columns not present in the instance
+		if insert && models.IsGenerated(table.Columns[i], intanceValue.Field(i)) {
+			continue
+		}
+
+		//The rows inserted are not removed, so a soft delete timestamp not set is left NULL
+		if insert && table.Columns[i].SoftDelete && table.Columns[i].IsTime && intanceValue.Field(i).IsZero() {
+			continue
+		}
+
 		if table.Columns[i].IsComplex {
-			//_, value, err = GetGoedbTagTypeAndValueOfIndexField(instanceType, intanceValue, "pk", i)
 			complexType := instanceType.Field(i).Type
 			complexValue := intanceValue.Field(i)
 			_, value, err = models.GetGoedbTagTypeAndValueOfForeignKeyReference(complexType, complexValue, "pk,unique", table.Columns[i].ForeignKey)
@@ -344,28 +1052,72 @@ func getColumnsAndValues(table models.Table, instance interface{}) (columns []st
 			value = intanceValue.Field(i)
 		}
 
-		switch table.Columns[i].ColumnType {
-		case reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int, reflect.Int64, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint, reflect.Uint64:
-			//values += strconv.FormatInt(value.Int(), 10) + ","
-			values = append(values, strconv.FormatInt(value.Int(), 10))
-		case reflect.Float32, reflect.Float64:
-			//values += strconv.FormatFloat(value.Float(), 'f', 6, 64) + ","
-			values = append(values, strconv.FormatFloat(value.Float(), 'f', 6, 64))
-		case reflect.Bool:
-			if value.Bool() {
-				//values += "1,"
-				values = append(values, "1")
-			} else {
-				//values += "0,"
-				values = append(values, "0")
-			}
-		case reflect.String:
-			//values += "'" + value.String() + "',"
-			values = append(values, "'"+value.String()+"'")
-		}
-		//columns += metatable.Columns[i].Title + ","
+		values = append(values, models.ColumnValue(table.Columns[i], value))
 		columns = append(columns, table.Columns[i].Title)
 	}
-	//return strCols[:len(strCols)-1], strValues[:len(strValues)-1], nil
 	return columns, values, err
 }
+
+func getColumnTitles(columns []models.Column) []string {
+	titles := make([]string, 0, len(columns))
+	for _, column := range columns {
+		titles = append(titles, column.Title)
+	}
+	return titles
+}
+
+func containsName(names []string, name string) bool {
+	for _, value := range names {
+		if strings.EqualFold(value, name) {
+			return true
+		}
+	}
+	return false
+}
+
+func containsColumn(columns []models.ColumnSchema, name string) bool {
+	for _, column := range columns {
+		if strings.EqualFold(column.Name, name) {
+			return true
+		}
+	}
+	return false
+}
+
+func getColumn(table models.Table, name string) (models.Column, bool) {
+	for _, column := range table.Columns {
+		if strings.EqualFold(column.Title, name) {
+			return column, true
+		}
+	}
+	return models.Column{}, false
+}
+
+func getColumnOrField(table models.Table, name string) (models.Column, bool) {
+	if column, ok := getColumn(table, name); ok {
+		return column, true
+	}
+	for _, column := range table.Columns {
+		if column.Field == name {
+			return column, true
+		}
+	}
+	return models.Column{}, false
+}
+
+// columnValue returns the value written into a column when it is not read from a field
+func columnValue(column models.Column, value interface{}) interface{} {
+	if column.IsJSON && value != nil {
+		return models.ColumnValue(column, reflect.ValueOf(value))
+	}
+	return models.DatabaseValue(value)
+}
+
+func findColumn(table models.Table, name string) bool {
+	for _, column := range table.Columns {
+		if strings.EqualFold(column.Title, name) {
+			return true
+		}
+	}
+	return false
+}
@@ -2,13 +2,29 @@ package dbaccess
 
 import (
 	"reflect"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/plopezm/goedb/database/dbaccess/dialect"
 
 	"github.com/plopezm/goedb/database/models"
 )
 
+func TestGetSQLDatabaseAccess(t *testing.T) {
+	databaseAccess, err := GetSQLDatabaseAccess("mysql")
+	if err != nil {
+		t.Fatalf("GetSQLDatabaseAccess() error = %v", err)
+	}
+	if _, ok := databaseAccess.Dialect.(*dialect.MySQLDialect); !ok {
+		t.Errorf("GetSQLDatabaseAccess() dialect = %T, want *dialect.MySQLDialect", databaseAccess.Dialect)
+	}
+
+	if _, err = GetSQLDatabaseAccess("sqlserver"); err == nil {
+		t.Errorf("GetSQLDatabaseAccess() expected an error for a driver without dialect")
+	}
+}
+
 func TestSQLDialect_Create(t *testing.T) {
 	type args struct {
 		table models.Table
@@ -19,6 +35,7 @@ func TestSQLDialect_Create(t *testing.T) {
 		specifics dialect.Dialect
 		args      args
 		want      string
+		wantErr   bool
 	}{
 		// TODO: Add test cases.
 		{
@@ -44,7 +61,7 @@ func TestSQLDialect_Create(t *testing.T) {
 					},
 				},
 			},
-			want: "CREATE TABLE Table1 (PKColumn BIGINT PRIMARY KEY AUTOINCREMENT,NormalColumnString VARCHAR)",
+			want: `CREATE TABLE "Table1" ("PKColumn" BIGINT PRIMARY KEY AUTOINCREMENT,"NormalColumnString" VARCHAR)`,
 		},
 		{
 			name: "TestCreateTableWithPrimaryKeys",
@@ -72,23 +89,183 @@ func TestSQLDialect_Create(t *testing.T) {
 						{
 							Title:      "NormalColumnString",
 							ColumnType: reflect.Struct,
+							Ignore:     true,
+						},
+					},
+				},
+			},
+			want: `CREATE TABLE "Table1" ("PKColumn1" BIGINT,"PKColumn2" BIGINT,"NormalColumnString" VARCHAR, PRIMARY KEY ("PKColumn1","PKColumn2"))`,
+		},
+		{
+			name: "TestCreateTableWithUnknownType",
+			dialect: &SQLDatabaseAccess{
+				Dialect: new(dialect.SQLite3Dialect),
+			},
+			args: args{
+				table: models.Table{
+					Name: "Table1",
+					Columns: []models.Column{
+						{
+							Title:      "PKColumn",
+							PrimaryKey: true,
+							ColumnType: reflect.Uint64,
+						},
+						{
+							Title:      "Money",
+							ColumnType: reflect.Struct,
+						},
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "TestCreateTableMySQL",
+			dialect: &SQLDatabaseAccess{
+				Dialect: new(dialect.MySQLDialect),
+			},
+			args: args{
+				table: models.Table{
+					Name: "Table1",
+					Columns: []models.Column{
+						{
+							Title:         "PKColumn",
+							PrimaryKey:    true,
+							ColumnType:    reflect.Uint64,
+							AutoIncrement: true,
+						},
+						{
+							Title:      "NormalColumnString",
+							ColumnType: reflect.String,
 						},
 					},
 				},
 			},
-			want: "CREATE TABLE Table1 (PKColumn1 BIGINT,PKColumn2 BIGINT,NormalColumnString VARCHAR, PRIMARY KEY (PKColumn1,PKColumn2))",
+			want: "CREATE TABLE `Table1` (`PKColumn` BIGINT AUTO_INCREMENT,`NormalColumnString` VARCHAR(255), PRIMARY KEY (`PKColumn`)) ENGINE=InnoDB",
 		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			dialect := tt.dialect
-			if got := dialect.Create(tt.args.table); got != tt.want {
+			got, err := dialect.Create(tt.args.table)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("SQLDatabaseAccess.Create() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
 				t.Errorf("SQLDatabaseAccess.Create() = %v, want %v", got, tt.want)
 			}
 		})
 	}
 }
 
+func getGoedbTableAlterTest() models.Table {
+	return models.Table{
+		Name: "Table1",
+		Columns: []models.Column{
+			{
+				Title:         "PKColumn",
+				PrimaryKey:    true,
+				ColumnType:    reflect.Uint64,
+				AutoIncrement: true,
+			},
+			{
+				Title:      "NormalColumnString",
+				ColumnType: reflect.String,
+				Index:      true,
+			},
+			{
+				Title:      "NewColumnInt",
+				ColumnType: reflect.Int,
+				Default:    "1",
+			},
+			{
+				Title:      "NewColumnUnique",
+				ColumnType: reflect.String,
+				Unique:     true,
+			},
+		},
+	}
+}
+
+func TestSQLDialect_Alter(t *testing.T) {
+	schema := models.TableSchema{
+		Name: "Table1",
+		Columns: []models.ColumnSchema{
+			{Name: "pkcolumn", Type: "bigint"},
+			{Name: "normalcolumnstring", Type: "character varying"},
+			{Name: "oldcolumn", Type: "VARCHAR", Default: "'old'"},
+		},
+		PrimaryKeys: []string{"pkcolumn"},
+	}
+	tests := []struct {
+		name    string
+		dialect *SQLDatabaseAccess
+		table   models.Table
+		schema  models.TableSchema
+		want    Migration
+		wantErr bool
+	}{
+		{
+			name:    "TestAlterTablePostgres",
+			dialect: &SQLDatabaseAccess{Dialect: new(dialect.PostgresDialect)},
+			table:   getGoedbTableAlterTest(),
+			schema:  schema,
+			want: Migration{
+				Sentences: []string{
+					`ALTER TABLE "Table1" ADD COLUMN "NewColumnInt" INTEGER DEFAULT 1`,
+					`ALTER TABLE "Table1" ADD COLUMN "NewColumnUnique" VARCHAR UNIQUE`,
+					`CREATE INDEX "idx_Table1_NormalColumnString" ON "Table1" ("NormalColumnString")`,
+				},
+			},
+		},
+		{
+			name:    "TestAlterTableSQLite3Rebuild",
+			dialect: &SQLDatabaseAccess{Dialect: new(dialect.SQLite3Dialect)},
+			table:   getGoedbTableAlterTest(),
+			schema:  schema,
+			want: Migration{
+				Sentences: []string{
+					`CREATE TABLE "goedb_rebuild_Table1" ("PKColumn" BIGINT PRIMARY KEY AUTOINCREMENT,"NormalColumnString" VARCHAR,"NewColumnInt" INTEGER DEFAULT 1,"NewColumnUnique" VARCHAR UNIQUE,"oldcolumn" VARCHAR DEFAULT 'old')`,
+					`INSERT INTO "goedb_rebuild_Table1" ("PKColumn","NormalColumnString","oldcolumn") SELECT "PKColumn","NormalColumnString","oldcolumn" FROM "Table1"`,
+					`DROP TABLE "Table1"`,
+					`ALTER TABLE "goedb_rebuild_Table1" RENAME TO "Table1"`,
+					`CREATE INDEX "idx_Table1_NormalColumnString" ON "Table1" ("NormalColumnString")`,
+				},
+				Rebuild:        true,
+				ForeignKeysOff: "PRAGMA foreign_keys = OFF",
+				ForeignKeysOn:  "PRAGMA foreign_keys = ON",
+			},
+		},
+		{
+			name:    "TestAlterTablePostgresPrimaryKey",
+			dialect: &SQLDatabaseAccess{Dialect: new(dialect.PostgresDialect)},
+			table:   getGoedbTableAlterTest(),
+			schema:  models.TableSchema{Name: "Table1", Columns: schema.Columns},
+			wantErr: true,
+		},
+		{
+			name:    "TestAlterTableNoChanges",
+			dialect: &SQLDatabaseAccess{Dialect: new(dialect.SQLite3Dialect)},
+			table:   models.Table{Name: "Table1", Columns: getGoedbTableAlterTest().Columns[:2]},
+			schema:  models.TableSchema{Name: "Table1", Columns: schema.Columns, PrimaryKeys: schema.PrimaryKeys, Indexes: []string{"idx_Table1_NormalColumnString"}},
+			want:    Migration{Sentences: []string{}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.dialect.Alter(tt.table, tt.schema)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("SQLDatabaseAccess.Alter() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("SQLDatabaseAccess.Alter() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
 func getGoedbTableTest1() models.Table {
 
 	type TestTable struct {
@@ -146,9 +323,9 @@ func getGoedbTableMapTest() (modelMap map[string]models.Table) {
 		TestTableWithFKName TestTableWithFK `goedb:"pk,fk=TestTableWithFK(Name)"`
 	}
 	modelMap = make(map[string]models.Table)
-	modelMap["TestTable"] = models.ParseModel(&TestTable{})
-	modelMap["TestTableWithFK"] = models.ParseModel(&TestTableWithFK{})
-	modelMap["TestTableWithFK2"] = models.ParseModel(&TestTableWithFK2{})
+	modelMap[models.ModelKey(reflect.TypeOf(TestTable{}))] = models.ParseModel(&TestTable{})
+	modelMap[models.ModelKey(reflect.TypeOf(TestTableWithFK{}))] = models.ParseModel(&TestTableWithFK{})
+	modelMap[models.ModelKey(reflect.TypeOf(TestTableWithFK2{}))] = models.ParseModel(&TestTableWithFK2{})
 	return modelMap
 }
 
@@ -171,8 +348,8 @@ func Test_generateSQLQuery(t *testing.T) {
 				table:    getGoedbTableTest1(),
 				modelMap: getGoedbTableMapTest(),
 			},
-			wantQuery:       "SELECT TestTableWithFK.Name,TestTable.ID,TestTable.Name,TestTableWithFK.Desc FROM TestTableWithFK,TestTable",
-			wantConstraints: " AND TestTableWithFK.TestTableName = TestTable.Name",
+			wantQuery:       `SELECT "TestTableWithFK"."Name","TestTable"."ID","TestTable"."Name","TestTableWithFK"."Desc" FROM "TestTableWithFK","TestTable"`,
+			wantConstraints: ` AND "TestTableWithFK"."TestTableName" = "TestTable"."Name"`,
 		},
 		{
 			name: "TestGenerateSQLQueryMoreThanOneStructAsDependency",
@@ -180,13 +357,13 @@ func Test_generateSQLQuery(t *testing.T) {
 				table:    getGoedbTableTest2(),
 				modelMap: getGoedbTableMapTest(),
 			},
-			wantQuery:       "SELECT TestTableWithFK2.Name,TestTableWithFK.Name,TestTable.ID,TestTable.Name,TestTableWithFK.Desc FROM TestTableWithFK2,TestTableWithFK,TestTable",
-			wantConstraints: " AND TestTableWithFK2.TestTableWithFKName = TestTableWithFK.Name AND TestTableWithFK.TestTableName = TestTable.Name",
+			wantQuery:       `SELECT "TestTableWithFK2"."Name","TestTableWithFK"."Name","TestTable"."ID","TestTable"."Name","TestTableWithFK"."Desc" FROM "TestTableWithFK2","TestTableWithFK","TestTable"`,
+			wantConstraints: ` AND "TestTableWithFK2"."TestTableWithFKName" = "TestTableWithFK"."Name" AND "TestTableWithFK"."TestTableName" = "TestTable"."Name"`,
 		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			gotQuery, gotConstraints, err := generateSQLQuery(tt.args.table, tt.args.modelMap)
+			gotQuery, gotConstraints, _, err := generateSQLQuery(tt.args.table, tt.args.modelMap, new(dialect.SQLite3Dialect), false)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("generateSQLQuery() error = %v, wantErr %v", err, tt.wantErr)
 				return
@@ -201,6 +378,42 @@ func Test_generateSQLQuery(t *testing.T) {
 	}
 }
 
+func Test_generateSQLQuery_Naming(t *testing.T) {
+	type TestTroop struct {
+		TroopID int `goedb:"pk"`
+		Name    string
+	}
+
+	type TestSoldier struct {
+		SoldierID int       `goedb:"pk"`
+		Troop     TestTroop `goedb:"fk=TestTroop(TroopID),column=troop"`
+	}
+
+	naming := models.PrefixNaming{Prefix: "app_", Naming: models.SnakeCaseNaming{}}
+	troops, err := models.ParseModelWithNaming(&TestTroop{}, naming)
+	if err != nil {
+		t.Fatalf("ParseModelWithNaming() error = %v", err)
+	}
+	soldiers, err := models.ParseModelWithNaming(&TestSoldier{}, naming)
+	if err != nil {
+		t.Fatalf("ParseModelWithNaming() error = %v", err)
+	}
+	modelMap := map[string]models.Table{
+		models.ModelKey(reflect.TypeOf(TestTroop{})):   troops,
+		models.ModelKey(reflect.TypeOf(TestSoldier{})): soldiers,
+	}
+	gotQuery, gotConstraints, _, err := generateSQLQuery(modelMap[models.ModelKey(reflect.TypeOf(TestSoldier{}))], modelMap, new(dialect.SQLite3Dialect), false)
+	if err != nil {
+		t.Fatalf("generateSQLQuery() error = %v", err)
+	}
+	if want := `SELECT "app_test_soldier"."soldier_id","app_test_troop"."troop_id","app_test_troop"."name" FROM "app_test_soldier","app_test_troop"`; gotQuery != want {
+		t.Errorf("generateSQLQuery() gotQuery = %v, want %v", gotQuery, want)
+	}
+	if want := ` AND "app_test_soldier"."troop" = "app_test_troop"."troop_id"`; gotConstraints != want {
+		t.Errorf("generateSQLQuery() gotConstraints = %v, want %v", gotConstraints, want)
+	}
+}
+
 func getGoedbTableTest1Value() interface{} {
 
 	type TestTable struct {
@@ -232,7 +445,7 @@ func Test_getPrimaryKeysAndValues(t *testing.T) {
 		name            string
 		args            args
 		wantColumnName  []string
-		wantColumnValue []string
+		wantColumnValue []interface{}
 		wantErr         bool
 	}{
 		// TODO: Add test cases.
@@ -243,7 +456,7 @@ func Test_getPrimaryKeysAndValues(t *testing.T) {
 				obj: getGoedbTableTest1Value(),
 			},
 			wantColumnName:  []string{"Name", "TestTableName"},
-			wantColumnValue: []string{"'TestTableWithFK-Name'", "'TestTableName-Name-ID'"},
+			wantColumnValue: []interface{}{"TestTableWithFK-Name", "TestTableName-Name-ID"},
 		},
 	}
 	for _, tt := range tests {
@@ -272,7 +485,7 @@ func Test_getColumnsAndValues(t *testing.T) {
 		name        string
 		args        args
 		wantColumns []string
-		wantValues  []string
+		wantValues  []interface{}
 		wantErr     bool
 	}{
 		// TODO: Add test cases.
@@ -283,12 +496,12 @@ func Test_getColumnsAndValues(t *testing.T) {
 				instance: getGoedbTableTest1Value(),
 			},
 			wantColumns: []string{"Name", "TestTableName", "Desc"},
-			wantValues:  []string{"'TestTableWithFK-Name'", "'TestTableName-Name-ID'", "'testing description'"},
+			wantValues:  []interface{}{"TestTableWithFK-Name", "TestTableName-Name-ID", "testing description"},
 		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			gotColumns, gotValues, err := getColumnsAndValues(tt.args.table, tt.args.instance)
+			gotColumns, gotValues, err := getColumnsAndValues(tt.args.table, tt.args.instance, false)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("getColumnsAndValuesSQL() error = %v, wantErr %v", err, tt.wantErr)
 				return
@@ -303,18 +516,41 @@ func Test_getColumnsAndValues(t *testing.T) {
 	}
 }
 
+func getGoedbTableQuotedValue() interface{} {
+
+	type TestTable struct {
+		ID   uint64 `goedb:"pk,autoincrement"`
+		Name string `goedb:"unique"`
+	}
+
+	type TestTableWithFK struct {
+		Name          string    `goedb:"pk"`
+		TestTableName TestTable `goedb:"pk,fk=TestTable(Name)"`
+		Ignorable     bool      `goedb:"ignore"`
+		Desc          string
+	}
+
+	return &TestTableWithFK{
+		Name:          "O'Brien",
+		TestTableName: TestTable{ID: 1, Name: "'; DROP TABLE TestTable; --"},
+		Desc:          "it's",
+	}
+}
+
 func TestSQLDialect_First(t *testing.T) {
 	type args struct {
 		table    models.Table
 		where    string
+		params   map[string]interface{}
 		instance interface{}
 	}
 	tests := []struct {
-		name    string
-		dialect *SQLDatabaseAccess
-		args    args
-		want    string
-		wantErr bool
+		name     string
+		dialect  *SQLDatabaseAccess
+		args     args
+		want     string
+		wantArgs []interface{}
+		wantErr  bool
 	}{
 		// TODO: Add test cases.
 		{
@@ -323,9 +559,10 @@ func TestSQLDialect_First(t *testing.T) {
 				table:    getGoedbTableTest1(),
 				instance: getGoedbTableTest1Value(),
 			},
-			want:    "SELECT TestTableWithFK.Name,TestTable.ID,TestTable.Name,TestTableWithFK.Desc FROM TestTableWithFK,TestTable WHERE TestTableWithFK.Name='TestTableWithFK-Name' AND TestTableWithFK.TestTableName='TestTableName-Name-ID' AND TestTableWithFK.TestTableName = TestTable.Name",
-			wantErr: false,
-			dialect: &SQLDatabaseAccess{Models: getGoedbTableMapTest()},
+			want:     `SELECT "TestTableWithFK"."Name","TestTable"."ID","TestTable"."Name","TestTableWithFK"."Desc" FROM "TestTableWithFK","TestTable" WHERE "TestTableWithFK"."Name"=? AND "TestTableWithFK"."TestTableName"=? AND "TestTableWithFK"."TestTableName" = "TestTable"."Name"`,
+			wantArgs: []interface{}{"TestTableWithFK-Name", "TestTableName-Name-ID"},
+			wantErr:  false,
+			dialect:  &SQLDatabaseAccess{Models: getGoedbTableMapTest(), Dialect: new(dialect.SQLite3Dialect)},
 		},
 		{
 			name: "SQLDialect_First_WithWhere",
@@ -334,9 +571,33 @@ func TestSQLDialect_First(t *testing.T) {
 				instance: getGoedbTableTest1Value(),
 				where:    "TestTableWithFK.Desc = 'description1'",
 			},
-			want:    "SELECT TestTableWithFK.Name,TestTable.ID,TestTable.Name,TestTableWithFK.Desc FROM TestTableWithFK,TestTable WHERE TestTableWithFK.Desc = 'description1' AND TestTableWithFK.TestTableName = TestTable.Name",
+			want:    `SELECT "TestTableWithFK"."Name","TestTable"."ID","TestTable"."Name","TestTableWithFK"."Desc" FROM "TestTableWithFK","TestTable" WHERE TestTableWithFK.Desc = 'description1' AND "TestTableWithFK"."TestTableName" = "TestTable"."Name"`,
 			wantErr: false,
-			dialect: &SQLDatabaseAccess{Models: getGoedbTableMapTest()},
+			dialect: &SQLDatabaseAccess{Models: getGoedbTableMapTest(), Dialect: new(dialect.SQLite3Dialect)},
+		},
+		{
+			name: "SQLDialect_First_WithNamedParams",
+			args: args{
+				table:    getGoedbTableTest1(),
+				instance: getGoedbTableTest1Value(),
+				where:    "TestTableWithFK.Desc = :desc AND TestTableWithFK.Name = :name",
+				params:   map[string]interface{}{"name": "O'Brien", "desc": "description1"},
+			},
+			want:     `SELECT "TestTableWithFK"."Name","TestTable"."ID","TestTable"."Name","TestTableWithFK"."Desc" FROM "TestTableWithFK","TestTable" WHERE TestTableWithFK.Desc = ? AND TestTableWithFK.Name = ? AND "TestTableWithFK"."TestTableName" = "TestTable"."Name"`,
+			wantArgs: []interface{}{"description1", "O'Brien"},
+			wantErr:  false,
+			dialect:  &SQLDatabaseAccess{Models: getGoedbTableMapTest(), Dialect: new(dialect.SQLite3Dialect)},
+		},
+		{
+			name: "SQLDialect_First_WithMissingParam",
+			args: args{
+				table:    getGoedbTableTest1(),
+				instance: getGoedbTableTest1Value(),
+				where:    "TestTableWithFK.Desc = :desc",
+			},
+			want:    "",
+			wantErr: true,
+			dialect: &SQLDatabaseAccess{Models: getGoedbTableMapTest(), Dialect: new(dialect.SQLite3Dialect)},
 		},
 		{
 			name: "SQLDialect_First_NoModelFound",
@@ -346,13 +607,13 @@ func TestSQLDialect_First(t *testing.T) {
 			},
 			want:    "",
 			wantErr: true,
-			dialect: &SQLDatabaseAccess{},
+			dialect: &SQLDatabaseAccess{Dialect: new(dialect.SQLite3Dialect)},
 		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			dialect := tt.dialect
-			got, err := dialect.First(tt.args.table, tt.args.where, tt.args.instance)
+			got, gotArgs, err := dialect.First(tt.args.table, tt.args.where, tt.args.params, tt.args.instance)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("SQLDatabaseAccess.First() error = [%v], wantErr [%v]", err, tt.wantErr)
 				return
@@ -360,6 +621,11 @@ func TestSQLDialect_First(t *testing.T) {
 			if got != tt.want {
 				t.Errorf("SQLDatabaseAccess.First() = [%v], want [%v]", got, tt.want)
 			}
+			if len(gotArgs) != 0 || len(tt.wantArgs) != 0 {
+				if !reflect.DeepEqual(gotArgs, tt.wantArgs) {
+					t.Errorf("SQLDatabaseAccess.First() args = %v, want %v", gotArgs, tt.wantArgs)
+				}
+			}
 		})
 	}
 }
@@ -371,14 +637,16 @@ func TestSQLDialect_Find(t *testing.T) {
 	type args struct {
 		table    models.Table
 		where    string
+		params   map[string]interface{}
 		instance interface{}
 	}
 	tests := []struct {
-		name    string
-		fields  fields
-		args    args
-		want    string
-		wantErr bool
+		name     string
+		fields   fields
+		args     args
+		want     string
+		wantArgs []interface{}
+		wantErr  bool
 	}{
 		// TODO: Add test cases.
 		{
@@ -387,7 +655,7 @@ func TestSQLDialect_Find(t *testing.T) {
 				table:    getGoedbTableTest1(),
 				instance: getGoedbTableTest1Value(),
 			},
-			want:    "SELECT TestTableWithFK.Name,TestTable.ID,TestTable.Name,TestTableWithFK.Desc FROM TestTableWithFK,TestTable WHERE TestTableWithFK.TestTableName = TestTable.Name",
+			want:    `SELECT "TestTableWithFK"."Name","TestTable"."ID","TestTable"."Name","TestTableWithFK"."Desc" FROM "TestTableWithFK","TestTable" WHERE "TestTableWithFK"."TestTableName" = "TestTable"."Name"`,
 			wantErr: false,
 			fields: fields{
 				Models: getGoedbTableMapTest(),
@@ -400,21 +668,23 @@ func TestSQLDialect_Find(t *testing.T) {
 				instance: getGoedbTableTest1Value(),
 				where:    "TestTableWithFK.Desc = 'description1'",
 			},
-			want:    "SELECT TestTableWithFK.Name,TestTable.ID,TestTable.Name,TestTableWithFK.Desc FROM TestTableWithFK,TestTable WHERE TestTableWithFK.Desc = 'description1' AND TestTableWithFK.TestTableName = TestTable.Name",
+			want:    `SELECT "TestTableWithFK"."Name","TestTable"."ID","TestTable"."Name","TestTableWithFK"."Desc" FROM "TestTableWithFK","TestTable" WHERE TestTableWithFK.Desc = 'description1' AND "TestTableWithFK"."TestTableName" = "TestTable"."Name"`,
 			wantErr: false,
 			fields: fields{
 				Models: getGoedbTableMapTest(),
 			},
 		},
 		{
-			name: "SQLDialect_Find_WithModelNotFound",
+			name: "SQLDialect_Find_WithNamedParams",
 			args: args{
 				table:    getGoedbTableTest1(),
 				instance: getGoedbTableTest1Value(),
-				where:    "TestTableWithFK.Desc = 'description1'",
+				where:    "TestTableWithFK.Desc = :desc",
+				params:   map[string]interface{}{"desc": "'; DROP TABLE TestTable; --"},
 			},
-			want:    "SELECT TestTableWithFK.Name,TestTable.ID,TestTable.Name,TestTableWithFK.Desc FROM TestTableWithFK,TestTable WHERE TestTableWithFK.Desc = 'description1' AND TestTableWithFK.TestTableName = TestTable.Name",
-			wantErr: false,
+			want:     `SELECT "TestTableWithFK"."Name","TestTable"."ID","TestTable"."Name","TestTableWithFK"."Desc" FROM "TestTableWithFK","TestTable" WHERE TestTableWithFK.Desc = ? AND "TestTableWithFK"."TestTableName" = "TestTable"."Name"`,
+			wantArgs: []interface{}{"'; DROP TABLE TestTable; --"},
+			wantErr:  false,
 			fields: fields{
 				Models: getGoedbTableMapTest(),
 			},
@@ -423,9 +693,10 @@ func TestSQLDialect_Find(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			dialect := &SQLDatabaseAccess{
-				Models: tt.fields.Models,
+				Models:  tt.fields.Models,
+				Dialect: new(dialect.SQLite3Dialect),
 			}
-			got, err := dialect.Find(tt.args.table, tt.args.where, tt.args.instance)
+			got, gotArgs, err := dialect.Find(tt.args.table, tt.args.where, tt.args.params, tt.args.instance)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("SQLDatabaseAccess.Find() error = %v, wantErr %v", err, tt.wantErr)
 				return
@@ -433,10 +704,84 @@ func TestSQLDialect_Find(t *testing.T) {
 			if got != tt.want {
 				t.Errorf("SQLDatabaseAccess.Find() = %v, want %v", got, tt.want)
 			}
+			if len(gotArgs) != 0 || len(tt.wantArgs) != 0 {
+				if !reflect.DeepEqual(gotArgs, tt.wantArgs) {
+					t.Errorf("SQLDatabaseAccess.Find() args = %v, want %v", gotArgs, tt.wantArgs)
+				}
+			}
 		})
 	}
 }
 
+func TestSQLDialect_Select(t *testing.T) {
+	tests := []struct {
+		name     string
+		query    Query
+		want     string
+		wantArgs []interface{}
+		wantErr  bool
+	}{
+		{
+			name:  "SQLDialect_Select_OrderLimitOffset",
+			query: Query{OrderBy: "TestTableWithFK.Name DESC", Limit: 20, Offset: 40},
+			want:  `SELECT "TestTableWithFK"."Name","TestTable"."ID","TestTable"."Name","TestTableWithFK"."Desc" FROM "TestTableWithFK","TestTable" WHERE "TestTableWithFK"."TestTableName" = "TestTable"."Name" ORDER BY TestTableWithFK.Name DESC LIMIT 20 OFFSET 40`,
+		},
+		{
+			name:     "SQLDialect_Select_WhereAndOffset",
+			query:    Query{Where: "TestTableWithFK.Desc = :desc", Params: map[string]interface{}{"desc": "description1"}, Offset: 10},
+			want:     `SELECT "TestTableWithFK"."Name","TestTable"."ID","TestTable"."Name","TestTableWithFK"."Desc" FROM "TestTableWithFK","TestTable" WHERE TestTableWithFK.Desc = ? AND "TestTableWithFK"."TestTableName" = "TestTable"."Name" LIMIT -1 OFFSET 10`,
+			wantArgs: []interface{}{"description1"},
+		},
+		{
+			name:    "SQLDialect_Select_MissingParam",
+			query:   Query{Where: "TestTableWithFK.Desc = :desc"},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dialect := &SQLDatabaseAccess{
+				Models:  getGoedbTableMapTest(),
+				Dialect: new(dialect.SQLite3Dialect),
+			}
+			got, gotArgs, err := dialect.Select(getGoedbTableTest1(), tt.query)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("SQLDatabaseAccess.Select() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("SQLDatabaseAccess.Select() = %v, want %v", got, tt.want)
+			}
+			if !reflect.DeepEqual(gotArgs, tt.wantArgs) {
+				t.Errorf("SQLDatabaseAccess.Select() args = %v, want %v", gotArgs, tt.wantArgs)
+			}
+		})
+	}
+}
+
+func TestSQLDialect_JSONPath(t *testing.T) {
+	postgres := &SQLDatabaseAccess{Models: getGoedbTableMapTest(), Dialect: new(dialect.PostgresDialect)}
+	dialect := &SQLDatabaseAccess{Dialect: new(dialect.SQLite3Dialect)}
+	got, err := dialect.JSONPath("soldier.Settings", []string{"address", "city"}, "Madrid")
+	if err != nil || got != "json_extract(soldier.Settings, '$.address.city')" {
+		t.Errorf("SQLDatabaseAccess.JSONPath() = %v, %v", got, err)
+	}
+	if _, err = dialect.JSONPath("soldier.Settings", []string{"city') OR 1=1 --"}, "Madrid"); err == nil {
+		t.Errorf("SQLDatabaseAccess.JSONPath() accepted an invalid path element")
+	}
+
+	for _, value := range []interface{}{3, true} {
+		expression, err := postgres.JSONPath(`"TestTableWithFK"."Desc"`, []string{"level"}, value)
+		if err != nil {
+			t.Fatalf("SQLDatabaseAccess.JSONPath() error = %v", err)
+		}
+		got, args, err := postgres.Select(getGoedbTableTest1(), Query{Where: expression + " = :value", Params: map[string]interface{}{"value": value}})
+		if want := `WHERE ` + expression + ` = ? AND`; err != nil || !strings.Contains(got, want) || !reflect.DeepEqual(args, []interface{}{value}) {
+			t.Errorf("SQLDatabaseAccess.Select() = %v, %v, %v, want the condition %v", got, args, err, want)
+		}
+	}
+}
+
 func TestSQLDialect_Update(t *testing.T) {
 	type fields struct {
 		Models map[string]models.Table
@@ -446,11 +791,12 @@ func TestSQLDialect_Update(t *testing.T) {
 		instance interface{}
 	}
 	tests := []struct {
-		name    string
-		fields  fields
-		args    args
-		want    string
-		wantErr bool
+		name     string
+		fields   fields
+		args     args
+		want     string
+		wantArgs []interface{}
+		wantErr  bool
 	}{
 		// TODO: Add test cases.
 		{
@@ -459,8 +805,9 @@ func TestSQLDialect_Update(t *testing.T) {
 				table:    getGoedbTableTest1(),
 				instance: getGoedbTableTest1Value(),
 			},
-			want:    "UPDATE TestTableWithFK SET Name = 'TestTableWithFK-Name',TestTableName = 'TestTableName-Name-ID',Desc = 'testing description' WHERE TestTableWithFK.Name='TestTableWithFK-Name' AND TestTableWithFK.TestTableName='TestTableName-Name-ID'",
-			wantErr: false,
+			want:     `UPDATE "TestTableWithFK" SET "Name" = ?,"TestTableName" = ?,"Desc" = ? WHERE "TestTableWithFK"."Name"=? AND "TestTableWithFK"."TestTableName"=?`,
+			wantArgs: []interface{}{"TestTableWithFK-Name", "TestTableName-Name-ID", "testing description", "TestTableWithFK-Name", "TestTableName-Name-ID"},
+			wantErr:  false,
 			fields: fields{
 				Models: getGoedbTableMapTest(),
 			},
@@ -469,9 +816,10 @@ func TestSQLDialect_Update(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			dialect := &SQLDatabaseAccess{
-				Models: tt.fields.Models,
+				Models:  tt.fields.Models,
+				Dialect: new(dialect.SQLite3Dialect),
 			}
-			got, err := dialect.Update(tt.args.table, tt.args.instance)
+			got, gotArgs, err := dialect.Update(tt.args.table, tt.args.instance)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("SQLDatabaseAccess.Update() error = %v, wantErr %v", err, tt.wantErr)
 				return
@@ -479,10 +827,354 @@ func TestSQLDialect_Update(t *testing.T) {
 			if got != tt.want {
 				t.Errorf("SQLDatabaseAccess.Update() = %v, want %v", got, tt.want)
 			}
+			if !reflect.DeepEqual(gotArgs, tt.wantArgs) {
+				t.Errorf("SQLDatabaseAccess.Update() args = %v, want %v", gotArgs, tt.wantArgs)
+			}
 		})
 	}
 }
 
+func TestSQLDialect_Update_Version(t *testing.T) {
+	type TestTableVersion struct {
+		ID      uint64 `goedb:"pk,autoincrement"`
+		Name    string
+		Version int `goedb:"version"`
+	}
+	type TestTableInvalidVersion struct {
+		ID      uint64 `goedb:"pk,autoincrement"`
+		Version string `goedb:"version"`
+	}
+	table := models.ParseModel(&TestTableVersion{})
+	dialect := &SQLDatabaseAccess{Dialect: new(dialect.SQLite3Dialect)}
+	instance := &TestTableVersion{ID: 1, Name: "Ryan", Version: 3}
+
+	got, gotArgs, err := dialect.Update(table, instance)
+	if err != nil {
+		t.Fatalf("SQLDatabaseAccess.Update() error = %v", err)
+	}
+	if want := `UPDATE "TestTableVersion" SET "Name" = ?,"Version" = ? WHERE "TestTableVersion"."ID"=? AND "TestTableVersion"."Version"=?`; got != want {
+		t.Errorf("SQLDatabaseAccess.Update() = %v, want %v", got, want)
+	}
+	if want := []interface{}{"Ryan", int64(4), uint64(1), int64(3)}; !reflect.DeepEqual(gotArgs, want) {
+		t.Errorf("SQLDatabaseAccess.Update() args = %v, want %v", gotArgs, want)
+	}
+
+	if _, _, err := dialect.UpdateColumns(table, instance, []string{"Version"}); err == nil {
+		t.Errorf("SQLDatabaseAccess.UpdateColumns() of the version column, error expected")
+	}
+
+	got, _, err = dialect.UpdateWhere(table, map[string]interface{}{"Name": "Miller"}, "TestTableVersion.Name = :name", map[string]interface{}{"name": "Ryan"})
+	if err != nil {
+		t.Fatalf("SQLDatabaseAccess.UpdateWhere() error = %v", err)
+	}
+	if want := `UPDATE "TestTableVersion" SET "Name" = ?,"Version" = "Version" + 1 WHERE TestTableVersion.Name = ?`; got != want {
+		t.Errorf("SQLDatabaseAccess.UpdateWhere() = %v, want %v", got, want)
+	}
+
+	_, values, err := dialect.Snapshot(table, instance)
+	if _, ok := values["Version"]; err != nil || ok {
+		t.Errorf("SQLDatabaseAccess.Snapshot() = %v, %v, want the values without version", values, err)
+	}
+
+	if _, _, err := dialect.Update(models.ParseModel(&TestTableInvalidVersion{}), &TestTableInvalidVersion{ID: 1}); err == nil {
+		t.Errorf("SQLDatabaseAccess.Update() with a string version, error expected")
+	}
+}
+
+func TestSQLDialect_Update_Timestamps(t *testing.T) {
+	type TestTableTimestamps struct {
+		ID        uint64    `goedb:"pk,autoincrement"`
+		Name      string    `goedb:"unique"`
+		CreatedAt time.Time `goedb:"createdAt"`
+		UpdatedAt time.Time `goedb:"updatedAt"`
+	}
+	now := time.Date(2017, 11, 5, 9, 30, 0, 0, time.UTC)
+	instance := &TestTableTimestamps{ID: 1, Name: "Ryan", CreatedAt: now, UpdatedAt: now}
+	table := models.ParseModel(instance)
+	dialect := &SQLDatabaseAccess{Dialect: new(dialect.SQLite3Dialect)}
+
+	got, gotArgs, err := dialect.Update(table, instance)
+	if err != nil {
+		t.Fatalf("SQLDatabaseAccess.Update() error = %v", err)
+	}
+	if want := `UPDATE "TestTableTimestamps" SET "Name" = ?,"UpdatedAt" = ? WHERE "TestTableTimestamps"."ID"=?`; got != want {
+		t.Errorf("SQLDatabaseAccess.Update() = %v, want %v", got, want)
+	}
+	if want := []interface{}{"Ryan", now, uint64(1)}; !reflect.DeepEqual(gotArgs, want) {
+		t.Errorf("SQLDatabaseAccess.Update() args = %v, want %v", gotArgs, want)
+	}
+
+	got, _, err = dialect.UpdateColumns(table, instance, []string{"Name"})
+	if want := `UPDATE "TestTableTimestamps" SET "Name" = ?,"UpdatedAt" = ? WHERE "TestTableTimestamps"."ID"=?`; err != nil || got != want {
+		t.Errorf("SQLDatabaseAccess.UpdateColumns() = %v, %v, want %v", got, err, want)
+	}
+	for _, column := range []string{"CreatedAt", "UpdatedAt"} {
+		if _, _, err := dialect.UpdateColumns(table, instance, []string{column}); err == nil {
+			t.Errorf("SQLDatabaseAccess.UpdateColumns() of %v, error expected", column)
+		}
+	}
+
+	got, _, _, err = dialect.Upsert(table, instance, []string{"Name"})
+	if want := `INSERT INTO "TestTableTimestamps" ("ID","Name","CreatedAt","UpdatedAt") values(?,?,?,?) ON CONFLICT ("Name") DO UPDATE SET "UpdatedAt"=excluded."UpdatedAt"`; err != nil || got != want {
+		t.Errorf("SQLDatabaseAccess.Upsert() = %v, %v, want %v", got, err, want)
+	}
+}
+
+func TestSQLDialect_SoftDelete(t *testing.T) {
+	type TestSoftTroop struct {
+		ID        uint64     `goedb:"pk,autoincrement"`
+		Name      string     `goedb:"unique"`
+		DeletedAt *time.Time `goedb:"softDelete"`
+	}
+	type TestSoftSoldier struct {
+		ID      uint64        `goedb:"pk,autoincrement"`
+		Name    string        `goedb:"unique"`
+		Troop   TestSoftTroop `goedb:"fk=TestSoftTroop(ID)"`
+		Removed bool          `goedb:"softDelete"`
+	}
+	troops := models.ParseModel(&TestSoftTroop{})
+	soldiers := models.ParseModel(&TestSoftSoldier{})
+	access := &SQLDatabaseAccess{
+		Models: map[string]models.Table{
+			models.ModelKey(reflect.TypeOf(TestSoftTroop{})):   troops,
+			models.ModelKey(reflect.TypeOf(TestSoftSoldier{})): soldiers,
+		},
+		Dialect: new(dialect.SQLite3Dialect),
+	}
+	soldier := &TestSoftSoldier{ID: 2, Name: "Ryan", Troop: TestSoftTroop{ID: 1}}
+	selectSoldiers := `SELECT "TestSoftSoldier"."ID","TestSoftSoldier"."Name","TestSoftTroop"."ID","TestSoftTroop"."Name","TestSoftTroop"."DeletedAt","TestSoftSoldier"."Removed" FROM "TestSoftSoldier","TestSoftTroop"`
+	filters := ` AND ("TestSoftSoldier"."Removed" IS NULL OR "TestSoftSoldier"."Removed" = 0) AND "TestSoftTroop"."DeletedAt" IS NULL`
+
+	got, _, err := access.First(soldiers, "", nil, soldier)
+	if want := selectSoldiers + ` WHERE "TestSoftSoldier"."ID"=? AND "TestSoftSoldier"."Troop" = "TestSoftTroop"."ID"` + filters; err != nil || got != want {
+		t.Errorf("SQLDatabaseAccess.First() = %v, %v, want %v", got, err, want)
+	}
+
+	got, _, err = access.Select(soldiers, Query{Where: "TestSoftSoldier.Name = :a OR TestSoftSoldier.Name = :b", Params: map[string]interface{}{"a": "Ryan", "b": "Miller"}})
+	if want := selectSoldiers + ` WHERE (TestSoftSoldier.Name = ? OR TestSoftSoldier.Name = ?) AND "TestSoftSoldier"."Troop" = "TestSoftTroop"."ID"` + filters; err != nil || got != want {
+		t.Errorf("SQLDatabaseAccess.Select() = %v, %v, want %v", got, err, want)
+	}
+
+	got, _, err = access.Select(soldiers, Query{WithDeleted: true})
+	if want := selectSoldiers + ` WHERE "TestSoftSoldier"."Troop" = "TestSoftTroop"."ID"`; err != nil || got != want {
+		t.Errorf("SQLDatabaseAccess.Select() with deleted = %v, %v, want %v", got, err, want)
+	}
+
+	got, _, err = access.Unscoped().Find(troops, "", nil, &[]TestSoftTroop{})
+	if want := `SELECT "TestSoftTroop"."ID","TestSoftTroop"."Name","TestSoftTroop"."DeletedAt" FROM "TestSoftTroop"`; err != nil || got != want {
+		t.Errorf("SQLDatabaseAccess.Unscoped().Find() = %v, %v, want %v", got, err, want)
+	}
+
+	now := time.Date(2017, 11, 5, 9, 30, 0, 0, time.UTC)
+	got, gotArgs, err := access.SoftDelete(troops, "TestSoftTroop.Name = :name", map[string]interface{}{"name": "Easy"}, nil, now)
+	if want := `UPDATE "TestSoftTroop" SET "DeletedAt" = ? WHERE (TestSoftTroop.Name = ?) AND "TestSoftTroop"."DeletedAt" IS NULL`; err != nil || got != want {
+		t.Errorf("SQLDatabaseAccess.SoftDelete() = %v, %v, want %v", got, err, want)
+	}
+	if want := []interface{}{now, "Easy"}; !reflect.DeepEqual(gotArgs, want) {
+		t.Errorf("SQLDatabaseAccess.SoftDelete() args = %v, want %v", gotArgs, want)
+	}
+
+	got, gotArgs, err = access.SoftDelete(soldiers, "", nil, soldier, now)
+	if want := `UPDATE "TestSoftSoldier" SET "Removed" = 1 WHERE ("TestSoftSoldier"."ID"=?) AND ("TestSoftSoldier"."Removed" IS NULL OR "TestSoftSoldier"."Removed" = 0)`; err != nil || got != want {
+		t.Errorf("SQLDatabaseAccess.SoftDelete() = %v, %v, want %v", got, err, want)
+	}
+	if want := []interface{}{uint64(2)}; !reflect.DeepEqual(gotArgs, want) {
+		t.Errorf("SQLDatabaseAccess.SoftDelete() args = %v, want %v", gotArgs, want)
+	}
+
+	got, _, err = access.Restore(troops, "", nil, &TestSoftTroop{ID: 1})
+	if want := `UPDATE "TestSoftTroop" SET "DeletedAt" = NULL WHERE "TestSoftTroop"."ID"=?`; err != nil || got != want {
+		t.Errorf("SQLDatabaseAccess.Restore() = %v, %v, want %v", got, err, want)
+	}
+	postgres := &SQLDatabaseAccess{Models: access.Models, Dialect: new(dialect.PostgresDialect)}
+	got, _, err = postgres.Restore(soldiers, "", nil, soldier)
+	if want := `UPDATE "TestSoftSoldier" SET "Removed" = FALSE WHERE "TestSoftSoldier"."ID"=?`; err != nil || got != want {
+		t.Errorf("SQLDatabaseAccess.Restore() = %v, %v, want %v", got, err, want)
+	}
+
+	if _, _, err := access.SoftDelete(getGoedbTableTest1(), "", nil, getGoedbTableTest1Value(), now); err == nil {
+		t.Errorf("SQLDatabaseAccess.SoftDelete() of a table without softDelete column, error expected")
+	}
+	if _, _, err := access.UpdateColumns(soldiers, soldier, []string{"Removed"}); err == nil {
+		t.Errorf("SQLDatabaseAccess.UpdateColumns() of the softDelete column, error expected")
+	}
+}
+
+func TestSQLDialect_UpdateColumns(t *testing.T) {
+	tests := []struct {
+		name     string
+		columns  []string
+		want     string
+		wantArgs []interface{}
+		wantErr  bool
+	}{
+		{
+			name:     "SQLDialect_UpdateColumns",
+			columns:  []string{"Desc"},
+			want:     `UPDATE "TestTableWithFK" SET "Desc" = ? WHERE "TestTableWithFK"."Name"=? AND "TestTableWithFK"."TestTableName"=?`,
+			wantArgs: []interface{}{"testing description", "TestTableWithFK-Name", "TestTableName-Name-ID"},
+		},
+		{
+			name:    "SQLDialect_UpdateColumns_Primary_Key",
+			columns: []string{"Desc", "Name"},
+			wantErr: true,
+		},
+		{
+			name:    "SQLDialect_UpdateColumns_Ignored",
+			columns: []string{"Ignorable"},
+			wantErr: true,
+		},
+		{
+			name:    "SQLDialect_UpdateColumns_Unknown",
+			columns: []string{"Unknown"},
+			wantErr: true,
+		},
+		{
+			name:    "SQLDialect_UpdateColumns_Without_Columns",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dialect := &SQLDatabaseAccess{
+				Models:  getGoedbTableMapTest(),
+				Dialect: new(dialect.SQLite3Dialect),
+			}
+			got, gotArgs, err := dialect.UpdateColumns(getGoedbTableTest1(), getGoedbTableTest1Value(), tt.columns)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("SQLDatabaseAccess.UpdateColumns() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("SQLDatabaseAccess.UpdateColumns() = %v, want %v", got, tt.want)
+			}
+			if !reflect.DeepEqual(gotArgs, tt.wantArgs) {
+				t.Errorf("SQLDatabaseAccess.UpdateColumns() args = %v, want %v", gotArgs, tt.wantArgs)
+			}
+		})
+	}
+}
+
+func TestSQLDialect_Snapshot(t *testing.T) {
+	dialect := &SQLDatabaseAccess{Dialect: new(dialect.SQLite3Dialect)}
+	key, values, err := dialect.Snapshot(getGoedbTableTest1(), getGoedbTableTest1Value())
+	if err != nil {
+		t.Fatalf("SQLDatabaseAccess.Snapshot() error = %v", err)
+	}
+	if want := `[]interface {}{"TestTableWithFK-Name", "TestTableName-Name-ID"}`; key != want {
+		t.Errorf("SQLDatabaseAccess.Snapshot() key = %v, want %v", key, want)
+	}
+	want := map[string]interface{}{"Name": "TestTableWithFK-Name", "TestTableName": "TestTableName-Name-ID", "Desc": "testing description"}
+	if !reflect.DeepEqual(values, want) {
+		t.Errorf("SQLDatabaseAccess.Snapshot() values = %v, want %v", values, want)
+	}
+
+	type TestTableShared struct {
+		ID       int `goedb:"pk"`
+		Nickname *string
+		Data     []byte
+	}
+	nickname := "old"
+	instance := &TestTableShared{ID: 1, Nickname: &nickname, Data: []byte{0}}
+	table := models.ParseModel(instance)
+	_, before, err := dialect.Snapshot(table, instance)
+	if err != nil {
+		t.Fatalf("SQLDatabaseAccess.Snapshot() error = %v", err)
+	}
+	*instance.Nickname = "new"
+	instance.Data[0] = 1
+	_, after, _ := dialect.Snapshot(table, instance)
+	if reflect.DeepEqual(before["Nickname"], after["Nickname"]) || reflect.DeepEqual(before["Data"], after["Data"]) {
+		t.Errorf("SQLDatabaseAccess.Snapshot() values = %v, %v, the changes in place are not found", before, after)
+	}
+}
+
+func TestSQLDialect_UpdateWhere(t *testing.T) {
+	type args struct {
+		values map[string]interface{}
+		where  string
+		params map[string]interface{}
+	}
+	tests := []struct {
+		name     string
+		args     args
+		want     string
+		wantArgs []interface{}
+		wantErr  bool
+	}{
+		{
+			name: "SQLDialect_UpdateWhere",
+			args: args{
+				values: map[string]interface{}{"TestTableName": "TestTableName-Name-ID", "Desc": "updated"},
+				where:  "TestTableWithFK.Name = :name",
+				params: map[string]interface{}{"name": "TestTableWithFK-Name"},
+			},
+			want:     `UPDATE "TestTableWithFK" SET "Desc" = ?,"TestTableName" = ? WHERE TestTableWithFK.Name = ?`,
+			wantArgs: []interface{}{"updated", "TestTableName-Name-ID", "TestTableWithFK-Name"},
+		},
+		{
+			name: "SQLDialect_UpdateWhere_Unknown_Column",
+			args: args{
+				values: map[string]interface{}{"Unknown": "value"},
+				where:  "TestTableWithFK.Name = :name",
+				params: map[string]interface{}{"name": "TestTableWithFK-Name"},
+			},
+			wantErr: true,
+		},
+		{
+			name: "SQLDialect_UpdateWhere_Without_Values",
+			args: args{
+				where:  "TestTableWithFK.Name = :name",
+				params: map[string]interface{}{"name": "TestTableWithFK-Name"},
+			},
+			wantErr: true,
+		},
+		{
+			name: "SQLDialect_UpdateWhere_Without_Where",
+			args: args{
+				values: map[string]interface{}{"Desc": "updated"},
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dialect := &SQLDatabaseAccess{
+				Models:  getGoedbTableMapTest(),
+				Dialect: new(dialect.SQLite3Dialect),
+			}
+			got, gotArgs, err := dialect.UpdateWhere(getGoedbTableTest1(), tt.args.values, tt.args.where, tt.args.params)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("SQLDatabaseAccess.UpdateWhere() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("SQLDatabaseAccess.UpdateWhere() = %v, want %v", got, tt.want)
+			}
+			if !reflect.DeepEqual(gotArgs, tt.wantArgs) {
+				t.Errorf("SQLDatabaseAccess.UpdateWhere() args = %v, want %v", gotArgs, tt.wantArgs)
+			}
+		})
+	}
+}
+
+func TestSQLDialect_DeleteWhere(t *testing.T) {
+	dialect := &SQLDatabaseAccess{Dialect: new(dialect.SQLite3Dialect)}
+	got, gotArgs, err := dialect.DeleteWhere(getGoedbTableTest1(), "TestTableWithFK.Desc = :desc", map[string]interface{}{"desc": "old"})
+	if err != nil {
+		t.Fatalf("SQLDatabaseAccess.DeleteWhere() error = %v", err)
+	}
+	if want := `DELETE FROM "TestTableWithFK" WHERE TestTableWithFK.Desc = ?`; got != want {
+		t.Errorf("SQLDatabaseAccess.DeleteWhere() = %v, want %v", got, want)
+	}
+	if !reflect.DeepEqual(gotArgs, []interface{}{"old"}) {
+		t.Errorf("SQLDatabaseAccess.DeleteWhere() args = %v, want [old]", gotArgs)
+	}
+	if _, _, err := dialect.DeleteWhere(getGoedbTableTest1(), "", nil); err == nil {
+		t.Errorf("SQLDatabaseAccess.DeleteWhere() without where clause, error expected")
+	}
+}
+
 func TestSQLDialect_Delete(t *testing.T) {
 	type fields struct {
 		Models map[string]models.Table
@@ -490,14 +1182,16 @@ func TestSQLDialect_Delete(t *testing.T) {
 	type args struct {
 		table    models.Table
 		where    string
+		params   map[string]interface{}
 		instance interface{}
 	}
 	tests := []struct {
-		name    string
-		fields  fields
-		args    args
-		want    string
-		wantErr bool
+		name     string
+		fields   fields
+		args     args
+		want     string
+		wantArgs []interface{}
+		wantErr  bool
 	}{
 		// TODO: Add test cases.
 		{
@@ -506,8 +1200,9 @@ func TestSQLDialect_Delete(t *testing.T) {
 				table:    getGoedbTableTest1(),
 				instance: getGoedbTableTest1Value(),
 			},
-			want:    "DELETE FROM TestTableWithFK WHERE Name='TestTableWithFK-Name' AND TestTableName='TestTableName-Name-ID'",
-			wantErr: false,
+			want:     `DELETE FROM "TestTableWithFK" WHERE "Name"=? AND "TestTableName"=?`,
+			wantArgs: []interface{}{"TestTableWithFK-Name", "TestTableName-Name-ID"},
+			wantErr:  false,
 			fields: fields{
 				Models: getGoedbTableMapTest(),
 			},
@@ -517,10 +1212,12 @@ func TestSQLDialect_Delete(t *testing.T) {
 			args: args{
 				table:    getGoedbTableTest1(),
 				instance: getGoedbTableTest1Value(),
-				where:    "Name='TestTableWithFK-Name'",
+				where:    "Name = :name",
+				params:   map[string]interface{}{"name": "TestTableWithFK-Name"},
 			},
-			want:    "DELETE FROM TestTableWithFK WHERE Name='TestTableWithFK-Name'",
-			wantErr: false,
+			want:     `DELETE FROM "TestTableWithFK" WHERE Name = ?`,
+			wantArgs: []interface{}{"TestTableWithFK-Name"},
+			wantErr:  false,
 			fields: fields{
 				Models: getGoedbTableMapTest(),
 			},
@@ -529,9 +1226,10 @@ func TestSQLDialect_Delete(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			dialect := &SQLDatabaseAccess{
-				Models: tt.fields.Models,
+				Models:  tt.fields.Models,
+				Dialect: new(dialect.SQLite3Dialect),
 			}
-			got, err := dialect.Delete(tt.args.table, tt.args.where, tt.args.instance)
+			got, gotArgs, err := dialect.Delete(tt.args.table, tt.args.where, tt.args.params, tt.args.instance)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("SQLDatabaseAccess.Delete() error = %v, wantErr %v", err, tt.wantErr)
 				return
@@ -539,6 +1237,9 @@ func TestSQLDialect_Delete(t *testing.T) {
 			if got != tt.want {
 				t.Errorf("SQLDatabaseAccess.Delete() = %v, want %v", got, tt.want)
 			}
+			if !reflect.DeepEqual(gotArgs, tt.wantArgs) {
+				t.Errorf("SQLDatabaseAccess.Delete() args = %v, want %v", gotArgs, tt.wantArgs)
+			}
 		})
 	}
 }
@@ -549,6 +1250,7 @@ func TestSQLDialect_Drop(t *testing.T) {
 	}
 	type args struct {
 		tableName string
+		ifExists  bool
 	}
 	tests := []struct {
 		name   string
@@ -562,7 +1264,18 @@ func TestSQLDialect_Drop(t *testing.T) {
 			args: args{
 				tableName: "TableToRemove",
 			},
-			want: "DROP TABLE TableToRemove",
+			want: `DROP TABLE "TableToRemove"`,
+			fields: fields{
+				Models: getGoedbTableMapTest(),
+			},
+		},
+		{
+			name: "SQLDialect_Drop_IfExists",
+			args: args{
+				tableName: "TableToRemove",
+				ifExists:  true,
+			},
+			want: `DROP TABLE IF EXISTS "TableToRemove"`,
 			fields: fields{
 				Models: getGoedbTableMapTest(),
 			},
@@ -571,50 +1284,141 @@ func TestSQLDialect_Drop(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			dialect := &SQLDatabaseAccess{
-				Models: tt.fields.Models,
+				Models:  tt.fields.Models,
+				Dialect: new(dialect.SQLite3Dialect),
 			}
-			if got := dialect.Drop(tt.args.tableName); got != tt.want {
+			if got := dialect.Drop(tt.args.tableName, tt.args.ifExists); got != tt.want {
 				t.Errorf("SQLDatabaseAccess.Drop() = %v, want %v", got, tt.want)
 			}
 		})
 	}
 }
 
+func getGoedbTableGeneratedValue() interface{} {
+	type TestTableGenerated struct {
+		ID     uint64 `goedb:"pk,autoincrement"`
+		Name   string `goedb:"unique"`
+		Status string `goedb:"default='active'"`
+	}
+	return &TestTableGenerated{Name: "generated"}
+}
+
+func getGoedbTableGeneratedValueWithID(id uint64) interface{} {
+	instance := getGoedbTableGeneratedValue()
+	reflect.ValueOf(instance).Elem().FieldByName("ID").SetUint(id)
+	return instance
+}
+
+func TestSQLDialect_Rebind(t *testing.T) {
+	tests := []struct {
+		name      string
+		specifics dialect.Dialect
+		sql       string
+		want      string
+	}{
+		{
+			name:      "Postgres",
+			specifics: new(dialect.PostgresDialect),
+			sql:       `SELECT "Name?" FROM "Table1" WHERE "ID" = ? AND Name = 'who?' AND Desc = ?`,
+			want:      `SELECT "Name?" FROM "Table1" WHERE "ID" = $1 AND Name = 'who?' AND Desc = $2`,
+		},
+		{
+			name:      "SQLite3",
+			specifics: new(dialect.SQLite3Dialect),
+			sql:       `SELECT "Name" FROM "Table1" WHERE "ID" = ? AND Desc = ?`,
+			want:      `SELECT "Name" FROM "Table1" WHERE "ID" = ? AND Desc = ?`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dialect := &SQLDatabaseAccess{Dialect: tt.specifics}
+			if got := dialect.Rebind(tt.sql); got != tt.want {
+				t.Errorf("SQLDatabaseAccess.Rebind() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
 func TestSQLDialect_Insert(t *testing.T) {
 	type fields struct {
-		Models map[string]models.Table
+		Models  map[string]models.Table
+		Dialect dialect.Dialect
 	}
 	type args struct {
 		table    models.Table
 		instance interface{}
 	}
 	tests := []struct {
-		name    string
-		fields  fields
-		args    args
-		want    string
-		wantErr bool
+		name          string
+		fields        fields
+		args          args
+		want          string
+		wantArgs      []interface{}
+		wantReturning bool
+		wantErr       bool
 	}{
 		// TODO: Add test cases.
 		{
-			name: "SQLDialect_Delete_WithWhere",
+			name: "SQLDialect_Insert",
 			args: args{
 				table:    getGoedbTableTest1(),
 				instance: getGoedbTableTest1Value(),
 			},
-			want:    "INSERT INTO TestTableWithFK (Name,TestTableName,Desc) values('TestTableWithFK-Name','TestTableName-Name-ID','testing description')",
-			wantErr: false,
+			want:     `INSERT INTO "TestTableWithFK" ("Name","TestTableName","Desc") values(?,?,?)`,
+			wantArgs: []interface{}{"TestTableWithFK-Name", "TestTableName-Name-ID", "testing description"},
+			wantErr:  false,
 			fields: fields{
-				Models: getGoedbTableMapTest(),
+				Models:  getGoedbTableMapTest(),
+				Dialect: new(dialect.SQLite3Dialect),
+			},
+		},
+		{
+			name: "SQLDialect_Insert_QuotedValues",
+			args: args{
+				table:    getGoedbTableTest1(),
+				instance: getGoedbTableQuotedValue(),
+			},
+			want:     `INSERT INTO "TestTableWithFK" ("Name","TestTableName","Desc") values(?,?,?)`,
+			wantArgs: []interface{}{"O'Brien", "'; DROP TABLE TestTable; --", "it's"},
+			wantErr:  false,
+			fields: fields{
+				Models:  getGoedbTableMapTest(),
+				Dialect: new(dialect.SQLite3Dialect),
+			},
+		},
+		{
+			name: "SQLDialect_Insert_GeneratedColumnsPostgres",
+			args: args{
+				table:    models.ParseModel(getGoedbTableGeneratedValue()),
+				instance: getGoedbTableGeneratedValue(),
+			},
+			want:          `INSERT INTO "TestTableGenerated" ("Name") values(?) RETURNING "ID","Status"`,
+			wantArgs:      []interface{}{"generated"},
+			wantReturning: true,
+			fields: fields{
+				Dialect: new(dialect.PostgresDialect),
+			},
+		},
+		{
+			name: "SQLDialect_Insert_GeneratedColumnsSQLite",
+			args: args{
+				table:    models.ParseModel(getGoedbTableGeneratedValue()),
+				instance: getGoedbTableGeneratedValue(),
+			},
+			want:     `INSERT INTO "TestTableGenerated" ("Name") values(?)`,
+			wantArgs: []interface{}{"generated"},
+			fields: fields{
+				Dialect: new(dialect.SQLite3Dialect),
 			},
 		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			dialect := &SQLDatabaseAccess{
-				Models: tt.fields.Models,
+				Models:  tt.fields.Models,
+				Dialect: tt.fields.Dialect,
 			}
-			got, err := dialect.Insert(tt.args.table, tt.args.instance)
+			got, gotArgs, gotReturning, err := dialect.Insert(tt.args.table, tt.args.instance)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("SQLDatabaseAccess.Insert() error = %v, wantErr %v", err, tt.wantErr)
 				return
@@ -622,6 +1426,242 @@ func TestSQLDialect_Insert(t *testing.T) {
 			if got != tt.want {
 				t.Errorf("SQLDatabaseAccess.Insert() = %v, want %v", got, tt.want)
 			}
+			if !reflect.DeepEqual(gotArgs, tt.wantArgs) {
+				t.Errorf("SQLDatabaseAccess.Insert() args = %v, want %v", gotArgs, tt.wantArgs)
+			}
+			if gotReturning != tt.wantReturning {
+				t.Errorf("SQLDatabaseAccess.Insert() returning = %v, want %v", gotReturning, tt.wantReturning)
+			}
+		})
+	}
+}
+
+func TestSQLDialect_InsertedRow(t *testing.T) {
+	table := models.ParseModel(getGoedbTableGeneratedValue())
+	mysql := &SQLDatabaseAccess{Dialect: new(dialect.MySQLDialect)}
+	dialect := &SQLDatabaseAccess{Dialect: new(dialect.SQLite3Dialect)}
+
+	generated := dialect.Generated(table, getGoedbTableGeneratedValue())
+	if len(generated) != 2 || generated[0].Title != "ID" || generated[1].Title != "Status" {
+		t.Errorf("SQLDatabaseAccess.Generated() = %v, want [ID Status]", generated)
+	}
+
+	got, gotArgs, ok := dialect.InsertedRow(table, generated, getGoedbTableGeneratedValue(), 7)
+	if want := `SELECT "ID","Status" FROM "TestTableGenerated" WHERE "ID" = ?`; !ok || got != want {
+		t.Errorf("SQLDatabaseAccess.InsertedRow() = %v, %v, want %v", got, ok, want)
+	}
+	if !reflect.DeepEqual(gotArgs, []interface{}{int64(7)}) {
+		t.Errorf("SQLDatabaseAccess.InsertedRow() args = %v, want [7]", gotArgs)
+	}
+
+	type withDefault struct {
+		Name  string `goedb:"pk"`
+		Level int    `goedb:"default=1"`
+	}
+	type withoutKey struct {
+		Name  string
+		Level int `goedb:"default=1"`
+	}
+	keyed := models.ParseModel(&withDefault{})
+	got, gotArgs, ok = mysql.InsertedRow(keyed, mysql.Generated(keyed, &withDefault{Name: "Ryan"}), &withDefault{Name: "Ryan"}, 0)
+	if want := "SELECT `Level` FROM `withDefault` WHERE `Name` = ?"; !ok || got != want {
+		t.Errorf("SQLDatabaseAccess.InsertedRow() = %v, %v, want %v", got, ok, want)
+	}
+	if !reflect.DeepEqual(gotArgs, []interface{}{"Ryan"}) {
+		t.Errorf("SQLDatabaseAccess.InsertedRow() args = %v, want [Ryan]", gotArgs)
+	}
+
+	keyless := models.ParseModel(&withoutKey{})
+	if _, _, ok = mysql.InsertedRow(keyless, mysql.Generated(keyless, &withoutKey{}), &withoutKey{}, 3); ok {
+		t.Errorf("SQLDatabaseAccess.InsertedRow() of a MySQL table without primary key found the row")
+	}
+	got, gotArgs, ok = dialect.InsertedRow(keyless, dialect.Generated(keyless, &withoutKey{}), &withoutKey{}, 3)
+	if want := `SELECT "Level" FROM "withoutKey" WHERE rowid = ?`; !ok || got != want || !reflect.DeepEqual(gotArgs, []interface{}{int64(3)}) {
+		t.Errorf("SQLDatabaseAccess.InsertedRow() = %v, %v, %v, want %v", got, gotArgs, ok, want)
+	}
+}
+
+func TestSQLDialect_ConflictColumns(t *testing.T) {
+	tests := []struct {
+		name     string
+		instance interface{}
+		columns  []string
+		want     []string
+		wantErr  bool
+	}{
+		{name: "PrimaryKeys", instance: getGoedbTableTest1Value(), want: []string{"Name", "TestTableName"}},
+		{name: "UniqueWhenPrimaryKeyIsGenerated", instance: getGoedbTableGeneratedValue(), want: []string{"Name"}},
+		{name: "Columns", instance: getGoedbTableGeneratedValue(), columns: []string{"Name"}, want: []string{"Name"}},
+		{name: "NotUniqueColumn", instance: getGoedbTableGeneratedValue(), columns: []string{"Status"}, wantErr: true},
+		{name: "GeneratedColumn", instance: getGoedbTableGeneratedValue(), columns: []string{"ID"}, wantErr: true},
+		{name: "AutoIncrementWithValue", instance: getGoedbTableGeneratedValueWithID(7), want: []string{"ID"}},
+		{name: "AutoIncrementColumnWithValue", instance: getGoedbTableGeneratedValueWithID(7), columns: []string{"ID"}, want: []string{"ID"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dialect := &SQLDatabaseAccess{Dialect: new(dialect.SQLite3Dialect)}
+			got, err := dialect.ConflictColumns(models.ParseModel(tt.instance), tt.instance, tt.columns)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("SQLDatabaseAccess.ConflictColumns() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("SQLDatabaseAccess.ConflictColumns() = %v, want %v", got, tt.want)
+			}
 		})
 	}
 }
+
+func TestSQLDialect_Upsert(t *testing.T) {
+	tests := []struct {
+		name            string
+		specifics       dialect.Dialect
+		instance        interface{}
+		conflictColumns []string
+		want            string
+		wantArgs        []interface{}
+		wantReturning   bool
+	}{
+		{
+			name:            "SQLite3",
+			specifics:       new(dialect.SQLite3Dialect),
+			instance:        getGoedbTableTest1Value(),
+			conflictColumns: []string{"Name", "TestTableName"},
+			want:            `INSERT INTO "TestTableWithFK" ("Name","TestTableName","Desc") values(?,?,?) ON CONFLICT ("Name","TestTableName") DO UPDATE SET "Desc"=excluded."Desc"`,
+			wantArgs:        []interface{}{"TestTableWithFK-Name", "TestTableName-Name-ID", "testing description"},
+		},
+		{
+			name:            "PostgresReturning",
+			specifics:       new(dialect.PostgresDialect),
+			instance:        getGoedbTableGeneratedValue(),
+			conflictColumns: []string{"Name"},
+			want:            `INSERT INTO "TestTableGenerated" ("Name") values(?) ON CONFLICT ("Name") DO NOTHING RETURNING "ID","Status"`,
+			wantArgs:        []interface{}{"generated"},
+			wantReturning:   true,
+		},
+		{
+			name:            "AutoIncrementWithValue",
+			specifics:       new(dialect.SQLite3Dialect),
+			instance:        getGoedbTableGeneratedValueWithID(7),
+			conflictColumns: []string{"ID"},
+			want:            `INSERT INTO "TestTableGenerated" ("ID","Name") values(?,?) ON CONFLICT ("ID") DO UPDATE SET "Name"=excluded."Name"`,
+			wantArgs:        []interface{}{uint64(7), "generated"},
+		},
+		{
+			name:            "MySQL",
+			specifics:       new(dialect.MySQLDialect),
+			instance:        getGoedbTableTest1Value(),
+			conflictColumns: []string{"Name", "TestTableName"},
+			want:            "INSERT INTO `TestTableWithFK` (`Name`,`TestTableName`,`Desc`) values(?,?,?) ON DUPLICATE KEY UPDATE `Desc`=VALUES(`Desc`)",
+			wantArgs:        []interface{}{"TestTableWithFK-Name", "TestTableName-Name-ID", "testing description"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dialect := &SQLDatabaseAccess{Dialect: tt.specifics}
+			got, gotArgs, gotReturning, err := dialect.Upsert(models.ParseModel(tt.instance), tt.instance, tt.conflictColumns)
+			if err != nil {
+				t.Errorf("SQLDatabaseAccess.Upsert() error = %v", err)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("SQLDatabaseAccess.Upsert() = %v, want %v", got, tt.want)
+			}
+			if !reflect.DeepEqual(gotArgs, tt.wantArgs) {
+				t.Errorf("SQLDatabaseAccess.Upsert() args = %v, want %v", gotArgs, tt.wantArgs)
+			}
+			if gotReturning != tt.wantReturning {
+				t.Errorf("SQLDatabaseAccess.Upsert() returning = %v, want %v", gotReturning, tt.wantReturning)
+			}
+		})
+	}
+}
+
+func TestSQLDialect_UpsertedRow(t *testing.T) {
+	instance := getGoedbTableGeneratedValue()
+	table := models.ParseModel(instance)
+	dialect := &SQLDatabaseAccess{Dialect: new(dialect.SQLite3Dialect)}
+
+	got, gotArgs, err := dialect.UpsertedRow(table, dialect.Generated(table, instance), []string{"Name"}, instance)
+	if err != nil {
+		t.Fatalf("SQLDatabaseAccess.UpsertedRow() error = %v", err)
+	}
+	if want := `SELECT "ID","Status" FROM "TestTableGenerated" WHERE "Name" = ?`; got != want {
+		t.Errorf("SQLDatabaseAccess.UpsertedRow() = %v, want %v", got, want)
+	}
+	if !reflect.DeepEqual(gotArgs, []interface{}{"generated"}) {
+		t.Errorf("SQLDatabaseAccess.UpsertedRow() args = %v, want [generated]", gotArgs)
+	}
+
+	instance = getGoedbTableGeneratedValueWithID(7)
+	got, gotArgs, err = dialect.UpsertedRow(table, dialect.Generated(table, instance), []string{"ID"}, instance)
+	if err != nil {
+		t.Fatalf("SQLDatabaseAccess.UpsertedRow() error = %v", err)
+	}
+	if want := `SELECT "ID","Status" FROM "TestTableGenerated" WHERE "ID" = ?`; got != want {
+		t.Errorf("SQLDatabaseAccess.UpsertedRow() = %v, want %v", got, want)
+	}
+	if !reflect.DeepEqual(gotArgs, []interface{}{uint64(7)}) {
+		t.Errorf("SQLDatabaseAccess.UpsertedRow() args = %v, want [7]", gotArgs)
+	}
+}
+
+func TestSQLDialect_InsertAll(t *testing.T) {
+	type TestTableBatch struct {
+		ID     uint64 `goedb:"pk,autoincrement"`
+		Name   string `goedb:"unique"`
+		Status string `goedb:"default='active'"`
+	}
+	table := models.ParseModel(&TestTableBatch{})
+
+	t.Run("ColumnsChanged", func(t *testing.T) {
+		dialect := &SQLDatabaseAccess{Dialect: new(dialect.PostgresDialect)}
+		instances := []interface{}{
+			&TestTableBatch{Name: "Ryan"},
+			&TestTableBatch{Name: "Miller"},
+			&TestTableBatch{Name: "Steve", Status: "retired"},
+		}
+		got, err := dialect.InsertAll(table, instances)
+		if err != nil {
+			t.Fatalf("SQLDatabaseAccess.InsertAll() error = %v", err)
+		}
+		want := []Batch{
+			{
+				SQL:       `INSERT INTO "TestTableBatch" ("Name") values(?),(?) RETURNING "ID","Status"`,
+				Args:      []interface{}{"Ryan", "Miller"},
+				Instances: instances[:2],
+				Returning: true,
+			},
+			{
+				SQL:       `INSERT INTO "TestTableBatch" ("Name","Status") values(?,?) RETURNING "ID"`,
+				Args:      []interface{}{"Steve", "retired"},
+				Instances: instances[2:],
+				Returning: true,
+			},
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("SQLDatabaseAccess.InsertAll() = %v, want %v", got, want)
+		}
+	})
+
+	t.Run("MaxParameters", func(t *testing.T) {
+		dialect := &SQLDatabaseAccess{Dialect: new(dialect.SQLite3Dialect)}
+		instances := make([]interface{}, 0)
+		for i := 0; i < 1000; i++ {
+			instances = append(instances, &TestTableBatch{Name: "Soldier", Status: "active"})
+		}
+		got, err := dialect.InsertAll(table, instances)
+		if err != nil {
+			t.Fatalf("SQLDatabaseAccess.InsertAll() error = %v", err)
+		}
+		if len(got) != 3 || len(got[0].Instances) != 499 || len(got[1].Instances) != 499 || len(got[2].Instances) != 2 {
+			t.Fatalf("SQLDatabaseAccess.InsertAll() returned %v batches, want 3 of 499, 499 and 2 rows", len(got))
+		}
+		if len(got[0].Args) != 998 || got[0].Returning {
+			t.Errorf("SQLDatabaseAccess.InsertAll() batch args = %v, returning = %v, want 998 and false", len(got[0].Args), got[0].Returning)
+		}
+		if want := `INSERT INTO "TestTableBatch" ("Name","Status") values(?,?),(?,?)`; got[2].SQL != want {
+			t.Errorf("SQLDatabaseAccess.InsertAll() = %v, want %v", got[2].SQL, want)
+		}
+	})
+}
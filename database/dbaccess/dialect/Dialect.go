@@ -1,8 +1,184 @@
 package dialect
 
-import "github.com/plopezm/goedb/database/models"
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
 
-//DBAccess is a small change in a dbaccess, it will be used for similar databases
+	"github.com/jmoiron/sqlx"
+	"github.com/plopezm/goedb/database/models"
+)
+
+// DBAccess is a small change in a dbaccess, it will be used for similar databases
 type Dialect interface {
 	GetSQLCreateTableColumn(value models.Column) (sqlColumnLine string, primaryKey string, constraints string, err error)
+	// GetSQLType returns the SQL type of a column, the one set with the type annotation or the one mapped from its Go type
+	GetSQLType(value models.Column) (string, error)
+	// GetSQLCreateTableOptions returns the options added after the columns of a CREATE TABLE, e.g. the engine of the table
+	GetSQLCreateTableOptions() string
+	// QuoteIdentifier returns the name of a table, column or index quoted, so reserved words and mixed case names can be used
+	QuoteIdentifier(name string) string
+	// GetPlaceholder returns the bindvar of the argument in the position index of a sentence, starting at 1
+	GetPlaceholder(index int) string
+	// GetMaxParameters returns the maximum number of bindvars accepted in a sentence
+	GetMaxParameters() int
+	// GetInsertedIDs returns the autoincrement values of the rows inserted by a multi-row INSERT using the last insert id
+	// of the sentence, ok is false if the database does not guarantee which values were used
+	GetInsertedIDs(lastInsertID int64, rows int) (ids []int64, ok bool)
+	// GetSQLBoolean returns the literal of a boolean value
+	GetSQLBoolean(value bool) string
+	// GetSQLLiteral returns the literal of a value (nil, bool, number, string, time.Time or driver.Valuer),
+	// it must only be used when the value cannot be bound as an argument
+	GetSQLLiteral(value interface{}) (string, error)
+	// GetSQLUpsert returns the clause added to an INSERT to update the columns of the row already found with
+	// the same conflict columns, the row is kept as it is if there are not columns to update
+	GetSQLUpsert(conflictColumns []string, updateColumns []string) string
+	// GetSQLDropTable returns the sentence to drop a table, if ifExists is true it does not fail when the table does not exist
+	GetSQLDropTable(tableName string, ifExists bool) string
+	// GetSQLReturning returns the clause used to get back the columns of an inserted row,
+	// it returns an empty string if the database does not support it
+	GetSQLReturning(columns []string) string
+	// GetSQLRowID returns the column whose value is the last insert id of a table without autoincrement primary key,
+	// it is used to read back the inserted row. It returns an empty string if the database has not got one
+	GetSQLRowID() string
+	// GetSQLLimitOffset returns the clause to limit the rows returned by a query, limit or offset are ignored if they are 0
+	GetSQLLimitOffset(limit int, offset int) string
+	// GetSQLJSONPath returns the expression to get the value of a path (e.g. ["address", "city"]) from a JSON column,
+	// the value it is compared with is used to get a comparable type (e.g. a number instead of text)
+	GetSQLJSONPath(column string, path []string, value interface{}) string
+	// GetTableSchema reads the columns, constraints and indexes of a table from the database,
+	// the schema returned has no columns if the table does not exist
+	GetTableSchema(ctx context.Context, db sqlx.QueryerContext, tableName string) (models.TableSchema, error)
+	// GetTables returns the names of the tables found in the database
+	GetTables(ctx context.Context, db sqlx.QueryerContext) ([]string, error)
+	// GetSQLAlterTable returns the sentences to apply the changes to an existing table,
+	// ok is false if the database cannot alter the table and it must be rebuilt
+	GetSQLAlterTable(tableName string, diff models.TableDiff) (sentences []string, ok bool, err error)
+	// GetSQLForeignKeys returns the sentence to enable or disable the foreign keys while a table is rebuilt
+	GetSQLForeignKeys(enabled bool) string
+}
+
+// quoteIdentifier surrounds the name with the quote character, the quote characters found in the name are doubled
+func quoteIdentifier(name string, quote string) string {
+	return quote + strings.Replace(name, quote, quote+quote, -1) + quote
+}
+
+// quoteLiteral returns the literal of a string, the single quotes found in it are doubled
+func quoteLiteral(value string) string {
+	return "'" + strings.Replace(value, "'", "''", -1) + "'"
+}
+
+// sqlLiteral returns the literal of a value using the boolean literals of the dialect, times are written in UTC using timeLayout
+func sqlLiteral(specifics Dialect, value interface{}, timeLayout string) (string, error) {
+	if valuer, ok := value.(driver.Valuer); ok {
+		var err error
+		if value, err = valuer.Value(); err != nil {
+			return "", err
+		}
+	}
+	if value == nil {
+		return "NULL", nil
+	}
+	if t, ok := value.(time.Time); ok {
+		return quoteLiteral(t.UTC().Format(timeLayout)), nil
+	}
+	switch v := reflect.ValueOf(value); v.Kind() {
+	case reflect.Bool:
+		return specifics.GetSQLBoolean(v.Bool()), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(v.Int(), 10), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return strconv.FormatUint(v.Uint(), 10), nil
+	case reflect.Float32, reflect.Float64:
+		return strconv.FormatFloat(v.Float(), 'g', -1, 64), nil
+	case reflect.String:
+		return quoteLiteral(v.String()), nil
+	case reflect.Ptr:
+		if v.IsNil() {
+			return "NULL", nil
+		}
+		return sqlLiteral(specifics, v.Elem().Interface(), timeLayout)
+	}
+	return "", errors.New("Literal of type " + reflect.TypeOf(value).String() + " not supported")
+}
+
+// joinQuoted returns the names quoted by the dialect separated by commas
+func joinQuoted(specifics Dialect, names []string) string {
+	quoted := make([]string, 0, len(names))
+	for _, name := range names {
+		quoted = append(quoted, specifics.QuoteIdentifier(name))
+	}
+	return strings.Join(quoted, ",")
+}
+
+// upsertOnConflict returns the ON CONFLICT clause used by Postgresql and SQLite3 (3.24 or later)
+func upsertOnConflict(specifics Dialect, conflictColumns []string, updateColumns []string) string {
+	sql := " ON CONFLICT (" + joinQuoted(specifics, conflictColumns) + ")"
+	if len(updateColumns) == 0 {
+		return sql + " DO NOTHING"
+	}
+	assignments := make([]string, 0, len(updateColumns))
+	for _, column := range updateColumns {
+		quoted := specifics.QuoteIdentifier(column)
+		assignments = append(assignments, quoted+"=excluded."+quoted)
+	}
+	return sql + " DO UPDATE SET " + strings.Join(assignments, ",")
+}
+
+// jsonKind returns the kind of the value compared with a JSON path, pointers are dereferenced
+func jsonKind(value interface{}) reflect.Kind {
+	v := reflect.ValueOf(value)
+	for v.Kind() == reflect.Ptr && !v.IsNil() {
+		v = v.Elem()
+	}
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return reflect.Float64
+	}
+	return v.Kind()
+}
+
+// dropTable returns the DROP TABLE sentence, IF EXISTS is supported by every dialect
+func dropTable(specifics Dialect, tableName string, ifExists bool) string {
+	if ifExists {
+		return "DROP TABLE IF EXISTS " + specifics.QuoteIdentifier(tableName)
+	}
+	return "DROP TABLE " + specifics.QuoteIdentifier(tableName)
+}
+
+// alterTableAddColumns returns the ALTER TABLE sentences used by Postgresql and MySQL to add the columns, unique
+// constraints and foreign keys of the diff, the primary key of an existing table cannot be changed
+func alterTableAddColumns(specifics Dialect, tableName string, diff models.TableDiff) ([]string, bool, error) {
+	if diff.PrimaryKeyChanged {
+		return nil, false, errors.New("The primary key of table " + tableName + " cannot be changed")
+	}
+	alterTable := "ALTER TABLE " + specifics.QuoteIdentifier(tableName)
+	sentences := make([]string, 0)
+	for _, column := range diff.Columns {
+		sqlColumn, _, constraints, err := specifics.GetSQLCreateTableColumn(column)
+		if err != nil {
+			return nil, false, err
+		}
+		sentences = append(sentences, alterTable+" ADD COLUMN "+sqlColumn[:len(sqlColumn)-1])
+		if constraints != "" {
+			sentences = append(sentences, alterTable+" ADD"+constraints[1:])
+		}
+	}
+	for _, column := range diff.Uniques {
+		sentences = append(sentences, alterTable+" ADD UNIQUE ("+specifics.QuoteIdentifier(column.Title)+")")
+	}
+	for _, column := range diff.ForeignKeys {
+		_, _, constraints, err := specifics.GetSQLCreateTableColumn(column)
+		if err != nil {
+			return nil, false, err
+		}
+		sentences = append(sentences, alterTable+" ADD"+constraints[1:])
+	}
+	return sentences, true, nil
 }
@@ -0,0 +1,211 @@
+package dialect
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/plopezm/goedb/database/models"
+)
+
+// mysqlMaxLimit is the biggest LIMIT accepted by MySQL, it is used when only an offset is set
+const mysqlMaxLimit = "18446744073709551615"
+
+// MySQLDialect contains a few functions that are different from standard sql dbaccess, it is used for MySQL and MariaDB
+type MySQLDialect struct {
+}
+
+// GetSQLCreateTableColumn returns the model of a column for MySQL
+func (dialect *MySQLDialect) GetSQLCreateTableColumn(value models.Column) (string, string, string, error) {
+	var pksFound string
+	var constraints string
+	sqlType, err := dialect.GetSQLType(value)
+	if err != nil {
+		return "", "", "", err
+	}
+	column := dialect.QuoteIdentifier(value.Title) + " " + sqlType
+
+	if value.NotNull {
+		column += " NOT NULL"
+	}
+
+	if value.AutoIncrement {
+		column += " AUTO_INCREMENT"
+	}
+
+	if value.Unique {
+		column += " UNIQUE"
+	}
+
+	if value.Default != "" {
+		column += " DEFAULT " + value.Default
+	}
+
+	if value.PrimaryKey {
+		pksFound += dialect.QuoteIdentifier(value.Title) + ","
+	}
+
+	if value.ForeignKey.IsForeignKey {
+		constraints += ", FOREIGN KEY (" + dialect.QuoteIdentifier(value.Title) + ") REFERENCES " + dialect.QuoteIdentifier(value.ForeignKey.ForeignKeyTableReference) +
+			"(" + dialect.QuoteIdentifier(value.ForeignKey.ForeignKeyColumnReference) + ")" + " ON DELETE CASCADE"
+	}
+	column += ","
+	return column, pksFound, constraints, nil
+}
+
+// GetSQLType returns the type of a column for MySQL, the strings are VARCHAR(255) because MySQL requires a length
+func (dialect *MySQLDialect) GetSQLType(value models.Column) (string, error) {
+	if value.SQLType != "" {
+		return value.SQLType, nil
+	}
+	if value.IsJSON {
+		return "JSON", nil
+	}
+	switch value.ColumnType {
+	case reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint:
+		return "INT", nil
+	case reflect.Int64, reflect.Uint64:
+		return "BIGINT", nil
+	case reflect.Float32, reflect.Float64:
+		return "DOUBLE", nil
+	case reflect.Bool:
+		return "BOOLEAN", nil
+	case reflect.String:
+		return "VARCHAR(255)", nil
+	case reflect.Struct:
+		if value.IsTime {
+			return "DATETIME(6)", nil
+		}
+	}
+	return "", errors.New("Type unknown")
+}
+
+// GetSQLCreateTableOptions returns the InnoDB engine, the foreign keys are ignored by the other engines of MySQL
+func (dialect *MySQLDialect) GetSQLCreateTableOptions() string {
+	return " ENGINE=InnoDB"
+}
+
+// QuoteIdentifier returns the name between backticks for MySQL
+func (dialect *MySQLDialect) QuoteIdentifier(name string) string {
+	return quoteIdentifier(name, "`")
+}
+
+// GetPlaceholder returns "?", the bindvar of MySQL
+func (dialect *MySQLDialect) GetPlaceholder(index int) string {
+	return "?"
+}
+
+// GetMaxParameters returns 65535, the maximum number of placeholders of a MySQL prepared statement
+func (dialect *MySQLDialect) GetMaxParameters() int {
+	return 65535
+}
+
+// GetInsertedIDs returns false, the last insert id is the first value generated but the values are not
+// consecutive when the interleaved lock mode of InnoDB (the default one in MySQL 8) is used
+func (dialect *MySQLDialect) GetInsertedIDs(lastInsertID int64, rows int) ([]int64, bool) {
+	return nil, false
+}
+
+// GetSQLBoolean returns TRUE or FALSE
+func (dialect *MySQLDialect) GetSQLBoolean(value bool) string {
+	if value {
+		return "TRUE"
+	}
+	return "FALSE"
+}
+
+// GetSQLLiteral returns the literal of a value for MySQL, times are written without offset because DATETIME has not got one
+func (dialect *MySQLDialect) GetSQLLiteral(value interface{}) (string, error) {
+	return sqlLiteral(dialect, value, "2006-01-02 15:04:05.999999")
+}
+
+// GetSQLDropTable returns the DROP TABLE sentence for MySQL
+func (dialect *MySQLDialect) GetSQLDropTable(tableName string, ifExists bool) string {
+	return dropTable(dialect, tableName, ifExists)
+}
+
+// GetSQLReturning returns an empty string, generated columns are read using the last insert id
+func (dialect *MySQLDialect) GetSQLReturning(columns []string) string {
+	return ""
+}
+
+// GetSQLRowID returns an empty string, MySQL has not got a row id so the last insert id is only
+// the value of the autoincrement column
+func (dialect *MySQLDialect) GetSQLRowID() string {
+	return ""
+}
+
+// GetSQLUpsert returns the ON DUPLICATE KEY UPDATE clause setting the columns to the values of the row inserted,
+// MySQL uses the primary key and unique constraints to find the duplicated row so the conflict columns are not written.
+// If there are not columns to update, the first conflict column is set to itself to keep the row
+func (dialect *MySQLDialect) GetSQLUpsert(conflictColumns []string, updateColumns []string) string {
+	if len(updateColumns) == 0 {
+		quoted := dialect.QuoteIdentifier(conflictColumns[0])
+		return " ON DUPLICATE KEY UPDATE " + quoted + "=" + quoted
+	}
+	assignments := make([]string, 0, len(updateColumns))
+	for _, column := range updateColumns {
+		quoted := dialect.QuoteIdentifier(column)
+		assignments = append(assignments, quoted+"=VALUES("+quoted+")")
+	}
+	return " ON DUPLICATE KEY UPDATE " + strings.Join(assignments, ",")
+}
+
+// GetSQLLimitOffset returns the LIMIT and OFFSET clauses for MySQL, OFFSET requires a LIMIT so the biggest one is used
+func (dialect *MySQLDialect) GetSQLLimitOffset(limit int, offset int) string {
+	if limit <= 0 && offset <= 0 {
+		return ""
+	}
+	sql := " LIMIT " + mysqlMaxLimit
+	if limit > 0 {
+		sql = " LIMIT " + strconv.Itoa(limit)
+	}
+	if offset > 0 {
+		sql += " OFFSET " + strconv.Itoa(offset)
+	}
+	return sql
+}
+
+// GetSQLJSONPath returns the text found in the path of a JSON column, numeric elements are array indexes.
+// The JSON value is not unquoted when it is compared with a number or a boolean, so the JSON types are compared
+func (dialect *MySQLDialect) GetSQLJSONPath(column string, path []string, value interface{}) string {
+	jsonPath := "$"
+	for _, element := range path {
+		if _, err := strconv.Atoi(element); err == nil {
+			jsonPath += "[" + element + "]"
+		} else {
+			jsonPath += "." + element
+		}
+	}
+	if kind := jsonKind(value); kind == reflect.Float64 || kind == reflect.Bool {
+		return "JSON_EXTRACT(" + column + ", '" + jsonPath + "')"
+	}
+	return "JSON_UNQUOTE(JSON_EXTRACT(" + column + ", '" + jsonPath + "'))"
+}
+
+// GetTableSchema reads the structure of a table of the current database from information_schema
+func (dialect *MySQLDialect) GetTableSchema(ctx context.Context, db sqlx.QueryerContext, tableName string) (schema models.TableSchema, err error) {
+	if schema, err = informationSchema(ctx, db, dialect, tableName, "DATABASE()", "column_type"); err != nil {
+		return schema, err
+	}
+	schema.Indexes, err = queryStrings(ctx, db, "SELECT DISTINCT index_name FROM information_schema.statistics WHERE table_schema = DATABASE() AND table_name = ?", tableName)
+	return schema, err
+}
+
+// GetTables returns the tables of the current database
+func (dialect *MySQLDialect) GetTables(ctx context.Context, db sqlx.QueryerContext) ([]string, error) {
+	return queryStrings(ctx, db, "SELECT table_name FROM information_schema.tables WHERE table_schema = DATABASE() AND table_type = 'BASE TABLE' ORDER BY table_name")
+}
+
+// GetSQLAlterTable returns the ALTER TABLE sentences for MySQL, the primary key of an existing table cannot be changed
+func (dialect *MySQLDialect) GetSQLAlterTable(tableName string, diff models.TableDiff) ([]string, bool, error) {
+	return alterTableAddColumns(dialect, tableName, diff)
+}
+
+// GetSQLForeignKeys returns an empty string, MySQL tables are never rebuilt
+func (dialect *MySQLDialect) GetSQLForeignKeys(enabled bool) string {
+	return ""
+}
@@ -0,0 +1,351 @@
+package dialect
+
+import (
+	"database/sql"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/plopezm/goedb/database/models"
+)
+
+func TestMySQLDialect_GetSQLCreateTableColumn(t *testing.T) {
+	type args struct {
+		value models.Column
+	}
+	tests := []struct {
+		name              string
+		args              args
+		wantSQLColumnLine string
+		wantPrimaryKey    string
+		wantConstraints   string
+		wantErr           bool
+	}{
+		{
+			name: "TestColumnPrimaryKeyAutoincrement",
+			args: args{
+				value: models.Column{
+					Title:         "PKColumn",
+					PrimaryKey:    true,
+					ColumnType:    reflect.Uint64,
+					AutoIncrement: true,
+				},
+			},
+			wantSQLColumnLine: "`PKColumn` BIGINT AUTO_INCREMENT,",
+			wantPrimaryKey:    "`PKColumn`,",
+		},
+		{
+			name: "TestColumnPrimaryKeyWithConstraints",
+			args: args{
+				value: models.Column{
+					Title:      "PKColumn",
+					PrimaryKey: true,
+					ColumnType: reflect.Int,
+					ForeignKey: models.ForeignKey{IsForeignKey: true, ForeignKeyTableReference: "OtherTable", ForeignKeyColumnReference: "OtherTablePK"},
+				},
+			},
+			wantSQLColumnLine: "`PKColumn` INT,",
+			wantPrimaryKey:    "`PKColumn`,",
+			wantConstraints:   ", FOREIGN KEY (`PKColumn`) REFERENCES `OtherTable`(`OtherTablePK`) ON DELETE CASCADE",
+		},
+		{
+			name: "TestErrorTypeNotFound",
+			args: args{
+				value: models.Column{
+					Title:      "PKColumn",
+					ColumnType: reflect.Struct,
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "TestStringColumnUnique",
+			args: args{
+				value: models.Column{
+					Title:      "UniqueColumnString",
+					ColumnType: reflect.String,
+					Unique:     true,
+				},
+			},
+			wantSQLColumnLine: "`UniqueColumnString` VARCHAR(255) UNIQUE,",
+		},
+		{
+			name: "TestFloatColumn",
+			args: args{
+				value: models.Column{
+					Title:      "FloatColumn",
+					ColumnType: reflect.Float64,
+				},
+			},
+			wantSQLColumnLine: "`FloatColumn` DOUBLE,",
+		},
+		{
+			name: "TestNotNullColumnWithDefault",
+			args: args{
+				value: models.Column{
+					Title:      "Status",
+					ColumnType: reflect.String,
+					NotNull:    true,
+					Default:    "'active'",
+				},
+			},
+			wantSQLColumnLine: "`Status` VARCHAR(255) NOT NULL DEFAULT 'active',",
+		},
+		{
+			name: "TestColumnWithSQLType",
+			args: args{
+				value: models.Column{
+					Title:      "Amount",
+					ColumnType: reflect.Int64,
+					SQLType:    "DECIMAL(12,2)",
+				},
+			},
+			wantSQLColumnLine: "`Amount` DECIMAL(12,2),",
+		},
+		{
+			name: "TestTimeColumn",
+			args: args{
+				value: models.Column{
+					Title:          "CreatedAt",
+					ColumnType:     reflect.Struct,
+					ColumnTypeName: "Time",
+					IsTime:         true,
+				},
+			},
+			wantSQLColumnLine: "`CreatedAt` DATETIME(6),",
+		},
+		{
+			name: "TestJSONColumn",
+			args: args{
+				value: models.Column{
+					Title:      "Settings",
+					ColumnType: reflect.Map,
+					IsJSON:     true,
+				},
+			},
+			wantSQLColumnLine: "`Settings` JSON,",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dialect := &MySQLDialect{}
+			got, got1, got2, err := dialect.GetSQLCreateTableColumn(tt.args.value)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("MySQLDialect.GetSQLCreateTableColumn() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.wantSQLColumnLine {
+				t.Errorf("MySQLDialect.GetSQLCreateTableColumn() got = %v, want %v", got, tt.wantSQLColumnLine)
+			}
+			if got1 != tt.wantPrimaryKey {
+				t.Errorf("MySQLDialect.GetSQLCreateTableColumn() got1 = %v, want %v", got1, tt.wantPrimaryKey)
+			}
+			if got2 != tt.wantConstraints {
+				t.Errorf("MySQLDialect.GetSQLCreateTableColumn() got2 = %v, want %v", got2, tt.wantConstraints)
+			}
+		})
+	}
+}
+
+func TestMySQLDialect_QuoteIdentifier(t *testing.T) {
+	tests := []struct {
+		name       string
+		identifier string
+		want       string
+	}{
+		{name: "ReservedWord", identifier: "Order", want: "`Order`"},
+		{name: "EmbeddedQuote", identifier: "Or`der", want: "`Or``der`"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dialect := &MySQLDialect{}
+			if got := dialect.QuoteIdentifier(tt.identifier); got != tt.want {
+				t.Errorf("MySQLDialect.QuoteIdentifier() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMySQLDialect_GetSQLCreateTableOptions(t *testing.T) {
+	dialect := &MySQLDialect{}
+	if got := dialect.GetSQLCreateTableOptions(); got != " ENGINE=InnoDB" {
+		t.Errorf("MySQLDialect.GetSQLCreateTableOptions() = %v, want %v", got, " ENGINE=InnoDB")
+	}
+}
+
+func TestMySQLDialect_GetSQLReturning(t *testing.T) {
+	dialect := &MySQLDialect{}
+	if got := dialect.GetSQLReturning([]string{"ID", "Status"}); got != "" {
+		t.Errorf("MySQLDialect.GetSQLReturning() = %v, want empty", got)
+	}
+}
+
+func TestMySQLDialect_GetSQLLimitOffset(t *testing.T) {
+	tests := []struct {
+		name   string
+		limit  int
+		offset int
+		want   string
+	}{
+		{name: "LimitAndOffset", limit: 20, offset: 40, want: " LIMIT 20 OFFSET 40"},
+		{name: "OnlyLimit", limit: 20, want: " LIMIT 20"},
+		{name: "OnlyOffset", offset: 40, want: " LIMIT 18446744073709551615 OFFSET 40"},
+		{name: "None", want: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dialect := &MySQLDialect{}
+			if got := dialect.GetSQLLimitOffset(tt.limit, tt.offset); got != tt.want {
+				t.Errorf("MySQLDialect.GetSQLLimitOffset() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMySQLDialect_GetSQLJSONPath(t *testing.T) {
+	tests := []struct {
+		name  string
+		path  []string
+		value interface{}
+		want  string
+	}{
+		{name: "Object", path: []string{"address", "city"}, want: "JSON_UNQUOTE(JSON_EXTRACT(soldier.Settings, '$.address.city'))"},
+		{name: "Array", path: []string{"tags", "0"}, want: "JSON_UNQUOTE(JSON_EXTRACT(soldier.Settings, '$.tags[0]'))"},
+		{name: "Number", path: []string{"level"}, value: 3, want: "JSON_EXTRACT(soldier.Settings, '$.level')"},
+		{name: "Boolean", path: []string{"active"}, value: false, want: "JSON_EXTRACT(soldier.Settings, '$.active')"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dialect := &MySQLDialect{}
+			if got := dialect.GetSQLJSONPath("soldier.Settings", tt.path, tt.value); got != tt.want {
+				t.Errorf("MySQLDialect.GetSQLJSONPath() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMySQLDialect_GetSQLAlterTable(t *testing.T) {
+	tests := []struct {
+		name    string
+		diff    models.TableDiff
+		want    []string
+		wantOk  bool
+		wantErr bool
+	}{
+		{
+			name: "NewColumnsAndConstraints",
+			diff: models.TableDiff{
+				Columns: []models.Column{
+					{Title: "Status", ColumnType: reflect.String, Default: "'active'"},
+					{Title: "OwnerID", ColumnType: reflect.Int, ForeignKey: models.ForeignKey{IsForeignKey: true, ForeignKeyTableReference: "Owner", ForeignKeyColumnReference: "ID"}},
+				},
+				Uniques: []models.Column{{Title: "Name", ColumnType: reflect.String, Unique: true}},
+			},
+			want: []string{
+				"ALTER TABLE `Table1` ADD COLUMN `Status` VARCHAR(255) DEFAULT 'active'",
+				"ALTER TABLE `Table1` ADD COLUMN `OwnerID` INT",
+				"ALTER TABLE `Table1` ADD FOREIGN KEY (`OwnerID`) REFERENCES `Owner`(`ID`) ON DELETE CASCADE",
+				"ALTER TABLE `Table1` ADD UNIQUE (`Name`)",
+			},
+			wantOk: true,
+		},
+		{
+			name:    "PrimaryKeyChanged",
+			diff:    models.TableDiff{PrimaryKeyChanged: true},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dialect := &MySQLDialect{}
+			got, ok, err := dialect.GetSQLAlterTable("Table1", tt.diff)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("MySQLDialect.GetSQLAlterTable() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if ok != tt.wantOk {
+				t.Errorf("MySQLDialect.GetSQLAlterTable() ok = %v, want %v", ok, tt.wantOk)
+			}
+			if tt.wantOk && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("MySQLDialect.GetSQLAlterTable() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMySQLDialect_GetPlaceholder(t *testing.T) {
+	dialect := &MySQLDialect{}
+	if got := dialect.GetPlaceholder(2); got != "?" {
+		t.Errorf("MySQLDialect.GetPlaceholder() = %v, want %v", got, "?")
+	}
+}
+
+func TestMySQLDialect_GetSQLLiteral(t *testing.T) {
+	created := time.Date(2020, 5, 17, 22, 30, 0, 0, time.FixedZone("CEST", 2*60*60))
+	tests := []struct {
+		name    string
+		value   interface{}
+		want    string
+		wantErr bool
+	}{
+		{name: "Nil", value: nil, want: "NULL"},
+		{name: "True", value: true, want: "TRUE"},
+		{name: "False", value: false, want: "FALSE"},
+		{name: "Int", value: int64(-42), want: "-42"},
+		{name: "Float", value: 1.5, want: "1.5"},
+		{name: "String", value: "O'Brien", want: "'O''Brien'"},
+		{name: "Time", value: created, want: "'2020-05-17 20:30:00'"},
+		{name: "Valuer", value: sql.NullString{String: "Ryan", Valid: true}, want: "'Ryan'"},
+		{name: "NilPointer", value: (*string)(nil), want: "NULL"},
+		{name: "Unsupported", value: []int{1}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dialect := &MySQLDialect{}
+			got, err := dialect.GetSQLLiteral(tt.value)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("MySQLDialect.GetSQLLiteral() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("MySQLDialect.GetSQLLiteral() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMySQLDialect_GetSQLUpsert(t *testing.T) {
+	tests := []struct {
+		name          string
+		updateColumns []string
+		want          string
+	}{
+		{name: "Update", updateColumns: []string{"Name", "Status"}, want: " ON DUPLICATE KEY UPDATE `Name`=VALUES(`Name`),`Status`=VALUES(`Status`)"},
+		{name: "Nothing", want: " ON DUPLICATE KEY UPDATE `ID`=`ID`"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dialect := &MySQLDialect{}
+			if got := dialect.GetSQLUpsert([]string{"ID"}, tt.updateColumns); got != tt.want {
+				t.Errorf("MySQLDialect.GetSQLUpsert() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMySQLDialect_GetSQLDropTable(t *testing.T) {
+	dialect := &MySQLDialect{}
+	if got := dialect.GetSQLDropTable("Table1", false); got != "DROP TABLE `Table1`" {
+		t.Errorf("MySQLDialect.GetSQLDropTable() = %v, want %v", got, "DROP TABLE `Table1`")
+	}
+	if got := dialect.GetSQLDropTable("Table1", true); got != "DROP TABLE IF EXISTS `Table1`" {
+		t.Errorf("MySQLDialect.GetSQLDropTable() = %v, want %v", got, "DROP TABLE IF EXISTS `Table1`")
+	}
+}
+
+func TestMySQLDialect_GetInsertedIDs(t *testing.T) {
+	dialect := &MySQLDialect{}
+	if ids, ok := dialect.GetInsertedIDs(10, 3); ok {
+		t.Errorf("MySQLDialect.GetInsertedIDs() = %v, want not ok", ids)
+	}
+}
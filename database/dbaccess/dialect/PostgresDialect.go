@@ -1,9 +1,13 @@
 package dialect
 
 import (
+	"context"
 	"errors"
 	"reflect"
+	"strconv"
+	"strings"
 
+	"github.com/jmoiron/sqlx"
 	"github.com/plopezm/goedb/database/models"
 )
 
@@ -15,42 +19,173 @@ type PostgresDialect struct {
 func (dialect *PostgresDialect) GetSQLCreateTableColumn(value models.Column) (string, string, string, error) {
 	var pksFound string
 	var constraints string
-	column := value.Title
+	sqlType, err := dialect.GetSQLType(value)
+	if err != nil {
+		return "", "", "", err
+	}
+	column := dialect.QuoteIdentifier(value.Title) + " " + sqlType
+
+	if value.NotNull {
+		column += " NOT NULL"
+	}
+
+	if value.Unique {
+		column += " UNIQUE"
+	}
+
+	if value.Default != "" {
+		column += " DEFAULT " + value.Default
+	}
+
+	if value.PrimaryKey {
+		pksFound += dialect.QuoteIdentifier(value.Title) + ","
+	}
 
+	if value.ForeignKey.IsForeignKey {
+		constraints += ", FOREIGN KEY (" + dialect.QuoteIdentifier(value.Title) + ") REFERENCES " + dialect.QuoteIdentifier(value.ForeignKey.ForeignKeyTableReference) +
+			"(" + dialect.QuoteIdentifier(value.ForeignKey.ForeignKeyColumnReference) + ")" + " ON DELETE CASCADE"
+	}
+	column += ","
+	return column, pksFound, constraints, nil
+}
+
+// GetSQLType returns the type of a column for Postgresql, autoincrement integers are SERIAL
+func (dialect *PostgresDialect) GetSQLType(value models.Column) (string, error) {
+	if value.SQLType != "" {
+		return value.SQLType, nil
+	}
+	if value.IsJSON {
+		return "JSONB", nil
+	}
 	switch value.ColumnType {
 	case reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint:
 		if value.AutoIncrement {
-			column += " SERIAL"
-		} else {
-			column += " INTEGER"
+			return "SERIAL", nil
 		}
+		return "INTEGER", nil
 	case reflect.Int64, reflect.Uint64:
 		if value.AutoIncrement {
-			column += " SERIAL"
-		} else {
-			column += " BIGINT"
+			return "SERIAL", nil
 		}
+		return "BIGINT", nil
 	case reflect.Float32, reflect.Float64:
-		column += " FLOAT"
+		return "FLOAT", nil
 	case reflect.Bool:
-		column += " BOOLEAN"
+		return "BOOLEAN", nil
 	case reflect.String:
-		column += " VARCHAR"
-	default:
-		return "", "", "", errors.New("Type unknown")
+		return "VARCHAR", nil
+	case reflect.Struct:
+		if value.IsTime {
+			return "TIMESTAMPTZ", nil
+		}
 	}
+	return "", errors.New("Type unknown")
+}
 
-	if value.Unique {
-		column += " UNIQUE"
+// GetSQLCreateTableOptions returns an empty string, Postgresql tables have no options
+func (dialect *PostgresDialect) GetSQLCreateTableOptions() string {
+	return ""
+}
+
+// QuoteIdentifier returns the name between double quotes for Postgresql
+func (dialect *PostgresDialect) QuoteIdentifier(name string) string {
+	return quoteIdentifier(name, `"`)
+}
+
+// GetPlaceholder returns the numbered bindvar of Postgresql ($1, $2...)
+func (dialect *PostgresDialect) GetPlaceholder(index int) string {
+	return "$" + strconv.Itoa(index)
+}
+
+// GetMaxParameters returns 65535, the maximum number of parameters of the Postgresql protocol
+func (dialect *PostgresDialect) GetMaxParameters() int {
+	return 65535
+}
+
+// GetInsertedIDs returns false, the generated values are read with RETURNING
+func (dialect *PostgresDialect) GetInsertedIDs(lastInsertID int64, rows int) ([]int64, bool) {
+	return nil, false
+}
+
+// GetSQLBoolean returns TRUE or FALSE
+func (dialect *PostgresDialect) GetSQLBoolean(value bool) string {
+	if value {
+		return "TRUE"
 	}
+	return "FALSE"
+}
 
-	if value.PrimaryKey {
-		pksFound += value.Title + ","
+// GetSQLLiteral returns the literal of a value for Postgresql, times are written with their offset
+func (dialect *PostgresDialect) GetSQLLiteral(value interface{}) (string, error) {
+	return sqlLiteral(dialect, value, "2006-01-02 15:04:05.999999-07:00")
+}
+
+// GetSQLUpsert returns the ON CONFLICT clause for Postgresql
+func (dialect *PostgresDialect) GetSQLUpsert(conflictColumns []string, updateColumns []string) string {
+	return upsertOnConflict(dialect, conflictColumns, updateColumns)
+}
+
+// GetSQLDropTable returns the DROP TABLE sentence for Postgresql
+func (dialect *PostgresDialect) GetSQLDropTable(tableName string, ifExists bool) string {
+	return dropTable(dialect, tableName, ifExists)
+}
+
+// GetSQLReturning returns the RETURNING clause for Postgresql
+func (dialect *PostgresDialect) GetSQLReturning(columns []string) string {
+	return " RETURNING " + joinQuoted(dialect, columns)
+}
+
+// GetSQLRowID returns an empty string, the generated columns are read with RETURNING
+func (dialect *PostgresDialect) GetSQLRowID() string {
+	return ""
+}
+
+// GetSQLLimitOffset returns the LIMIT and OFFSET clauses for Postgresql
+func (dialect *PostgresDialect) GetSQLLimitOffset(limit int, offset int) string {
+	var sql string
+	if limit > 0 {
+		sql += " LIMIT " + strconv.Itoa(limit)
 	}
+	if offset > 0 {
+		sql += " OFFSET " + strconv.Itoa(offset)
+	}
+	return sql
+}
 
-	if value.ForeignKey.IsForeignKey {
-		constraints += ", FOREIGN KEY (" + value.Title + ") REFERENCES " + value.ForeignKey.ForeignKeyTableReference + "(" + value.ForeignKey.ForeignKeyColumnReference + ")" + " ON DELETE CASCADE"
+// GetSQLJSONPath returns the text found in the path of a JSONB column, it is cast to numeric or boolean
+// when it is compared with a number or a boolean. CAST is used because the named parameters binding rewrites "::"
+func (dialect *PostgresDialect) GetSQLJSONPath(column string, path []string, value interface{}) string {
+	expression := column + " #>> '{" + strings.Join(path, ",") + "}'"
+	switch jsonKind(value) {
+	case reflect.Float64:
+		return "CAST(" + expression + " AS numeric)"
+	case reflect.Bool:
+		return "CAST(" + expression + " AS boolean)"
 	}
-	column += ","
-	return column, pksFound, constraints, nil
+	return expression
+}
+
+// GetTableSchema reads the structure of a table from information_schema and pg_indexes,
+// the tables are created with quoted names so the name is case sensitive
+func (dialect *PostgresDialect) GetTableSchema(ctx context.Context, db sqlx.QueryerContext, tableName string) (schema models.TableSchema, err error) {
+	if schema, err = informationSchema(ctx, db, dialect, tableName, "current_schema()", "data_type"); err != nil {
+		return schema, err
+	}
+	schema.Indexes, err = queryStrings(ctx, db, "SELECT indexname FROM pg_indexes WHERE schemaname = current_schema() AND tablename = $1", tableName)
+	return schema, err
+}
+
+// GetTables returns the tables of the current schema
+func (dialect *PostgresDialect) GetTables(ctx context.Context, db sqlx.QueryerContext) ([]string, error) {
+	return queryStrings(ctx, db, "SELECT table_name FROM information_schema.tables WHERE table_schema = current_schema() AND table_type = 'BASE TABLE' ORDER BY table_name")
+}
+
+// GetSQLAlterTable returns the ALTER TABLE sentences for Postgresql, the primary key of an existing table cannot be changed
+func (dialect *PostgresDialect) GetSQLAlterTable(tableName string, diff models.TableDiff) ([]string, bool, error) {
+	return alterTableAddColumns(dialect, tableName, diff)
+}
+
+// GetSQLForeignKeys returns an empty string, Postgresql tables are never rebuilt
+func (dialect *PostgresDialect) GetSQLForeignKeys(enabled bool) string {
+	return ""
 }
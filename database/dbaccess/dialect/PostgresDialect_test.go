@@ -1,8 +1,10 @@
 package dialect
 
 import (
+	"database/sql"
 	"reflect"
 	"testing"
+	"time"
 
 	"github.com/plopezm/goedb/database/models"
 )
@@ -31,8 +33,8 @@ func TestPostgresSpecifics_GetSQLCreateTableColumn(t *testing.T) {
 					AutoIncrement: true,
 				},
 			},
-			wantSQLColumnLine: "PKColumn SERIAL,",
-			wantPrimaryKey:    "PKColumn,",
+			wantSQLColumnLine: `"PKColumn" SERIAL,`,
+			wantPrimaryKey:    `"PKColumn",`,
 			wantConstraints:   "",
 		},
 		{
@@ -44,8 +46,8 @@ func TestPostgresSpecifics_GetSQLCreateTableColumn(t *testing.T) {
 					ColumnType: reflect.Int,
 				},
 			},
-			wantSQLColumnLine: "PKColumn INTEGER,",
-			wantPrimaryKey:    "PKColumn,",
+			wantSQLColumnLine: `"PKColumn" INTEGER,`,
+			wantPrimaryKey:    `"PKColumn",`,
 			wantConstraints:   "",
 		},
 		{
@@ -58,9 +60,9 @@ func TestPostgresSpecifics_GetSQLCreateTableColumn(t *testing.T) {
 					ForeignKey: models.ForeignKey{IsForeignKey: true, ForeignKeyTableReference: "OtherTable", ForeignKeyColumnReference: "OtherTablePK"},
 				},
 			},
-			wantSQLColumnLine: "PKColumn INTEGER,",
-			wantPrimaryKey:    "PKColumn,",
-			wantConstraints:   ", FOREIGN KEY (PKColumn) REFERENCES OtherTable(OtherTablePK) ON DELETE CASCADE",
+			wantSQLColumnLine: `"PKColumn" INTEGER,`,
+			wantPrimaryKey:    `"PKColumn",`,
+			wantConstraints:   `, FOREIGN KEY ("PKColumn") REFERENCES "OtherTable"("OtherTablePK") ON DELETE CASCADE`,
 		},
 		{
 			name: "TestErrorTypeNotFound",
@@ -83,7 +85,7 @@ func TestPostgresSpecifics_GetSQLCreateTableColumn(t *testing.T) {
 					Unique:     true,
 				},
 			},
-			wantSQLColumnLine: "UniqueColumnString VARCHAR UNIQUE,",
+			wantSQLColumnLine: `"UniqueColumnString" VARCHAR UNIQUE,`,
 		},
 		{
 			name: "TestNormalStringColumn",
@@ -93,7 +95,7 @@ func TestPostgresSpecifics_GetSQLCreateTableColumn(t *testing.T) {
 					ColumnType: reflect.String,
 				},
 			},
-			wantSQLColumnLine: "NormalColumnString VARCHAR,",
+			wantSQLColumnLine: `"NormalColumnString" VARCHAR,`,
 		},
 		{
 			name: "TestNormalStringColumn",
@@ -103,7 +105,7 @@ func TestPostgresSpecifics_GetSQLCreateTableColumn(t *testing.T) {
 					ColumnType: reflect.Float64,
 				},
 			},
-			wantSQLColumnLine: "NormalColumnString FLOAT,",
+			wantSQLColumnLine: `"NormalColumnString" FLOAT,`,
 		},
 		{
 			name: "TestNormalStringColumn",
@@ -113,7 +115,85 @@ func TestPostgresSpecifics_GetSQLCreateTableColumn(t *testing.T) {
 					ColumnType: reflect.Bool,
 				},
 			},
-			wantSQLColumnLine: "NormalColumnString BOOLEAN,",
+			wantSQLColumnLine: `"NormalColumnString" BOOLEAN,`,
+		},
+		{
+			name: "TestColumnWithDefault",
+			args: args{
+				value: models.Column{
+					Title:      "DefaultColumnString",
+					ColumnType: reflect.String,
+					Default:    "'active'",
+				},
+			},
+			wantSQLColumnLine: `"DefaultColumnString" VARCHAR DEFAULT 'active',`,
+		},
+		{
+			name: "TestNotNullColumn",
+			args: args{
+				value: models.Column{
+					Title:      "NotNullColumnString",
+					ColumnType: reflect.String,
+					NotNull:    true,
+					Unique:     true,
+				},
+			},
+			wantSQLColumnLine: `"NotNullColumnString" VARCHAR NOT NULL UNIQUE,`,
+		},
+		{
+			name: "TestNullableColumn",
+			args: args{
+				value: models.Column{
+					Title:      "NullableColumnInt",
+					ColumnType: reflect.Int64,
+				},
+			},
+			wantSQLColumnLine: `"NullableColumnInt" BIGINT,`,
+		},
+		{
+			name: "TestColumnWithSQLType",
+			args: args{
+				value: models.Column{
+					Title:      "Amount",
+					ColumnType: reflect.Int64,
+					SQLType:    "NUMERIC(12,2)",
+					NotNull:    true,
+				},
+			},
+			wantSQLColumnLine: `"Amount" NUMERIC(12,2) NOT NULL,`,
+		},
+		{
+			name: "TestCustomStructWithoutSQLType",
+			args: args{
+				value: models.Column{
+					Title:      "ID",
+					ColumnType: reflect.Struct,
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "TestTimeColumn",
+			args: args{
+				value: models.Column{
+					Title:          "CreatedAt",
+					ColumnType:     reflect.Struct,
+					ColumnTypeName: "Time",
+					IsTime:         true,
+				},
+			},
+			wantSQLColumnLine: `"CreatedAt" TIMESTAMPTZ,`,
+		},
+		{
+			name: "TestJSONColumn",
+			args: args{
+				value: models.Column{
+					Title:      "Settings",
+					ColumnType: reflect.Map,
+					IsJSON:     true,
+				},
+			},
+			wantSQLColumnLine: `"Settings" JSONB,`,
 		},
 	}
 	for _, tt := range tests {
@@ -136,3 +216,196 @@ func TestPostgresSpecifics_GetSQLCreateTableColumn(t *testing.T) {
 		})
 	}
 }
+
+func TestPostgresDialect_QuoteIdentifier(t *testing.T) {
+	tests := []struct {
+		name       string
+		identifier string
+		want       string
+	}{
+		{name: "ReservedWord", identifier: "Order", want: `"Order"`},
+		{name: "EmbeddedQuote", identifier: `Or"der`, want: `"Or""der"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dialect := &PostgresDialect{}
+			if got := dialect.QuoteIdentifier(tt.identifier); got != tt.want {
+				t.Errorf("PostgresDialect.QuoteIdentifier() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPostgresDialect_GetSQLReturning(t *testing.T) {
+	dialect := &PostgresDialect{}
+	if got := dialect.GetSQLReturning([]string{"ID", "Status"}); got != ` RETURNING "ID","Status"` {
+		t.Errorf("PostgresDialect.GetSQLReturning() = %v, want %v", got, ` RETURNING "ID","Status"`)
+	}
+}
+
+func TestPostgresDialect_GetSQLLimitOffset(t *testing.T) {
+	tests := []struct {
+		name   string
+		limit  int
+		offset int
+		want   string
+	}{
+		{name: "LimitAndOffset", limit: 20, offset: 40, want: " LIMIT 20 OFFSET 40"},
+		{name: "OnlyLimit", limit: 20, want: " LIMIT 20"},
+		{name: "OnlyOffset", offset: 40, want: " OFFSET 40"},
+		{name: "None", want: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dialect := &PostgresDialect{}
+			if got := dialect.GetSQLLimitOffset(tt.limit, tt.offset); got != tt.want {
+				t.Errorf("PostgresDialect.GetSQLLimitOffset() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPostgresDialect_GetSQLJSONPath(t *testing.T) {
+	tests := []struct {
+		name  string
+		path  []string
+		value interface{}
+		want  string
+	}{
+		{name: "Object", path: []string{"address", "city"}, want: "soldier.Settings #>> '{address,city}'"},
+		{name: "Array", path: []string{"tags", "0"}, want: "soldier.Settings #>> '{tags,0}'"},
+		{name: "Number", path: []string{"level"}, value: 3, want: "CAST(soldier.Settings #>> '{level}' AS numeric)"},
+		{name: "Float", path: []string{"ratio"}, value: 0.5, want: "CAST(soldier.Settings #>> '{ratio}' AS numeric)"},
+		{name: "Boolean", path: []string{"active"}, value: true, want: "CAST(soldier.Settings #>> '{active}' AS boolean)"},
+		{name: "Text", path: []string{"name"}, value: "Ryan", want: "soldier.Settings #>> '{name}'"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dialect := &PostgresDialect{}
+			if got := dialect.GetSQLJSONPath("soldier.Settings", tt.path, tt.value); got != tt.want {
+				t.Errorf("PostgresDialect.GetSQLJSONPath() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPostgresDialect_GetSQLAlterTable(t *testing.T) {
+	tests := []struct {
+		name    string
+		diff    models.TableDiff
+		want    []string
+		wantOk  bool
+		wantErr bool
+	}{
+		{
+			name: "NewColumnsAndConstraints",
+			diff: models.TableDiff{
+				Columns: []models.Column{
+					{Title: "Status", ColumnType: reflect.String, Default: "'active'"},
+					{Title: "OwnerID", ColumnType: reflect.Int, ForeignKey: models.ForeignKey{IsForeignKey: true, ForeignKeyTableReference: "Owner", ForeignKeyColumnReference: "ID"}},
+				},
+				Uniques:     []models.Column{{Title: "Name", ColumnType: reflect.String, Unique: true}},
+				ForeignKeys: []models.Column{{Title: "TeamID", ColumnType: reflect.Int, ForeignKey: models.ForeignKey{IsForeignKey: true, ForeignKeyTableReference: "Team", ForeignKeyColumnReference: "ID"}}},
+			},
+			want: []string{
+				`ALTER TABLE "Table1" ADD COLUMN "Status" VARCHAR DEFAULT 'active'`,
+				`ALTER TABLE "Table1" ADD COLUMN "OwnerID" INTEGER`,
+				`ALTER TABLE "Table1" ADD FOREIGN KEY ("OwnerID") REFERENCES "Owner"("ID") ON DELETE CASCADE`,
+				`ALTER TABLE "Table1" ADD UNIQUE ("Name")`,
+				`ALTER TABLE "Table1" ADD FOREIGN KEY ("TeamID") REFERENCES "Team"("ID") ON DELETE CASCADE`,
+			},
+			wantOk: true,
+		},
+		{
+			name:    "PrimaryKeyChanged",
+			diff:    models.TableDiff{PrimaryKeyChanged: true},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dialect := &PostgresDialect{}
+			got, ok, err := dialect.GetSQLAlterTable("Table1", tt.diff)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("PostgresDialect.GetSQLAlterTable() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if ok != tt.wantOk {
+				t.Errorf("PostgresDialect.GetSQLAlterTable() ok = %v, want %v", ok, tt.wantOk)
+			}
+			if tt.wantOk && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("PostgresDialect.GetSQLAlterTable() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPostgresDialect_GetPlaceholder(t *testing.T) {
+	dialect := &PostgresDialect{}
+	if got := dialect.GetPlaceholder(2); got != "$2" {
+		t.Errorf("PostgresDialect.GetPlaceholder() = %v, want %v", got, "$2")
+	}
+}
+
+func TestPostgresDialect_GetSQLLiteral(t *testing.T) {
+	created := time.Date(2020, 5, 17, 22, 30, 0, 0, time.FixedZone("CEST", 2*60*60))
+	tests := []struct {
+		name    string
+		value   interface{}
+		want    string
+		wantErr bool
+	}{
+		{name: "Nil", value: nil, want: "NULL"},
+		{name: "True", value: true, want: "TRUE"},
+		{name: "False", value: false, want: "FALSE"},
+		{name: "Int", value: int64(-42), want: "-42"},
+		{name: "Float", value: 1.5, want: "1.5"},
+		{name: "String", value: "O'Brien", want: "'O''Brien'"},
+		{name: "Time", value: created, want: "'2020-05-17 20:30:00+00:00'"},
+		{name: "Valuer", value: sql.NullString{String: "Ryan", Valid: true}, want: "'Ryan'"},
+		{name: "NilPointer", value: (*string)(nil), want: "NULL"},
+		{name: "Unsupported", value: []int{1}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dialect := &PostgresDialect{}
+			got, err := dialect.GetSQLLiteral(tt.value)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("PostgresDialect.GetSQLLiteral() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("PostgresDialect.GetSQLLiteral() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPostgresDialect_GetSQLUpsert(t *testing.T) {
+	tests := []struct {
+		name          string
+		updateColumns []string
+		want          string
+	}{
+		{name: "Update", updateColumns: []string{"Name", "Status"}, want: ` ON CONFLICT ("ID") DO UPDATE SET "Name"=excluded."Name","Status"=excluded."Status"`},
+		{name: "Nothing", want: ` ON CONFLICT ("ID") DO NOTHING`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dialect := &PostgresDialect{}
+			if got := dialect.GetSQLUpsert([]string{"ID"}, tt.updateColumns); got != tt.want {
+				t.Errorf("PostgresDialect.GetSQLUpsert() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPostgresDialect_GetSQLDropTable(t *testing.T) {
+	dialect := &PostgresDialect{}
+	if got := dialect.GetSQLDropTable("Table1", false); got != `DROP TABLE "Table1"` {
+		t.Errorf("PostgresDialect.GetSQLDropTable() = %v, want %v", got, `DROP TABLE "Table1"`)
+	}
+	if got := dialect.GetSQLDropTable("Table1", true); got != `DROP TABLE IF EXISTS "Table1"` {
+		t.Errorf("PostgresDialect.GetSQLDropTable() = %v, want %v", got, `DROP TABLE IF EXISTS "Table1"`)
+	}
+}
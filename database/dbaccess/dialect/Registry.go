@@ -0,0 +1,40 @@
+package dialect
+
+import (
+	"errors"
+	"sync"
+)
+
+var registry = struct {
+	sync.RWMutex
+	dialects map[string]Dialect
+}{
+	dialects: map[string]Dialect{
+		"sqlite3":  new(SQLite3Dialect),
+		"postgres": new(PostgresDialect),
+		"pgx":      new(PostgresDialect),
+		"mysql":    new(MySQLDialect),
+	},
+}
+
+// Register adds the dialect used for the databases opened with a database/sql driver name, a dialect
+// registered before for the same driver is replaced. It panics if the dialect is nil
+func Register(driverName string, dialect Dialect) {
+	if dialect == nil {
+		panic("goedb: Register dialect is nil for driver " + driverName)
+	}
+	registry.Lock()
+	defer registry.Unlock()
+	registry.dialects[driverName] = dialect
+}
+
+// Get returns the dialect registered for a driver name, an error is returned if there is not any
+func Get(driverName string) (Dialect, error) {
+	registry.RLock()
+	defer registry.RUnlock()
+	dialect, ok := registry.dialects[driverName]
+	if !ok {
+		return nil, errors.New("No dialect registered for driver \"" + driverName + "\"")
+	}
+	return dialect, nil
+}
@@ -0,0 +1,57 @@
+package dialect
+
+import (
+	"reflect"
+	"testing"
+)
+
+type testCockroachDialect struct {
+	PostgresDialect
+}
+
+func TestRegister(t *testing.T) {
+	Register("testcockroach", new(testCockroachDialect))
+
+	got, err := Get("testcockroach")
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	if _, ok := got.(*testCockroachDialect); !ok {
+		t.Errorf("Get() = %T, want *testCockroachDialect", got)
+	}
+}
+
+func TestRegister_Nil(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Errorf("Register() did not panic with a nil dialect")
+		}
+	}()
+	Register("testnil", nil)
+}
+
+func TestGet(t *testing.T) {
+	tests := []struct {
+		driverName string
+		want       Dialect
+		wantErr    bool
+	}{
+		{driverName: "sqlite3", want: new(SQLite3Dialect)},
+		{driverName: "postgres", want: new(PostgresDialect)},
+		{driverName: "pgx", want: new(PostgresDialect)},
+		{driverName: "mysql", want: new(MySQLDialect)},
+		{driverName: "sqlserver", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.driverName, func(t *testing.T) {
+			got, err := Get(tt.driverName)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Get() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !tt.wantErr && reflect.TypeOf(got) != reflect.TypeOf(tt.want) {
+				t.Errorf("Get() = %T, want %T", got, tt.want)
+			}
+		})
+	}
+}
@@ -1,9 +1,13 @@
 package dialect
 
 import (
+	"context"
 	"errors"
 	"reflect"
+	"strconv"
+	"strings"
 
+	"github.com/jmoiron/sqlx"
 	"github.com/plopezm/goedb/database/models"
 )
 
@@ -13,36 +17,224 @@ type SQLite3Dialect struct {
 
 // GetSQLCreateTableColumn returns the model of a column for SQLite3
 func (specifics *SQLite3Dialect) GetSQLCreateTableColumn(value models.Column) (sqlColumnLine string, primaryKey string, constraints string, err error) {
-	sqlColumnLine = value.Title
+	sqlType, err := specifics.GetSQLType(value)
+	if err != nil {
+		return "", "", "", err
+	}
+	sqlColumnLine = specifics.QuoteIdentifier(value.Title) + " " + sqlType
 
-	switch value.ColumnType {
-	case reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint:
-		sqlColumnLine += " INTEGER"
-	case reflect.Int64, reflect.Uint64:
-		sqlColumnLine += " BIGINT"
-	case reflect.Float32, reflect.Float64:
-		sqlColumnLine += " FLOAT"
-	case reflect.Bool:
-		sqlColumnLine += " BOOLEAN"
-	case reflect.String:
-		sqlColumnLine += " VARCHAR"
-	default:
-		return "", "", "", errors.New("Type unknown")
+	if value.NotNull {
+		sqlColumnLine += " NOT NULL"
 	}
 
 	if value.Unique {
 		sqlColumnLine += " UNIQUE"
 	}
 
+	if value.Default != "" {
+		sqlColumnLine += " DEFAULT " + value.Default
+	}
+
 	if value.PrimaryKey && value.AutoIncrement {
 		sqlColumnLine += " PRIMARY KEY AUTOINCREMENT"
 	} else if value.PrimaryKey {
-		primaryKey += value.Title + ","
+		primaryKey += specifics.QuoteIdentifier(value.Title) + ","
 	}
 
 	if value.ForeignKey.IsForeignKey {
-		constraints += ", FOREIGN KEY (" + value.Title + ") REFERENCES " + value.ForeignKey.ForeignKeyTableReference + "(" + value.ForeignKey.ForeignKeyColumnReference + ")" + " ON DELETE CASCADE"
+		constraints += ", FOREIGN KEY (" + specifics.QuoteIdentifier(value.Title) + ") REFERENCES " + specifics.QuoteIdentifier(value.ForeignKey.ForeignKeyTableReference) +
+			"(" + specifics.QuoteIdentifier(value.ForeignKey.ForeignKeyColumnReference) + ")" + " ON DELETE CASCADE"
 	}
 	sqlColumnLine += ","
 	return sqlColumnLine, primaryKey, constraints, nil
 }
+
+// GetSQLType returns the type of a column for SQLite3
+func (specifics *SQLite3Dialect) GetSQLType(value models.Column) (string, error) {
+	if value.SQLType != "" {
+		return value.SQLType, nil
+	}
+	if value.IsJSON {
+		return "TEXT", nil
+	}
+	switch value.ColumnType {
+	case reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint:
+		return "INTEGER", nil
+	case reflect.Int64, reflect.Uint64:
+		return "BIGINT", nil
+	case reflect.Float32, reflect.Float64:
+		return "FLOAT", nil
+	case reflect.Bool:
+		return "BOOLEAN", nil
+	case reflect.String:
+		return "VARCHAR", nil
+	case reflect.Struct:
+		if value.IsTime {
+			return "DATETIME", nil
+		}
+	}
+	return "", errors.New("Type unknown")
+}
+
+// GetSQLCreateTableOptions returns an empty string, SQLite3 tables have no options
+func (specifics *SQLite3Dialect) GetSQLCreateTableOptions() string {
+	return ""
+}
+
+// QuoteIdentifier returns the name between double quotes for SQLite3
+func (specifics *SQLite3Dialect) QuoteIdentifier(name string) string {
+	return quoteIdentifier(name, `"`)
+}
+
+// GetPlaceholder returns "?", the bindvar of SQLite3
+func (specifics *SQLite3Dialect) GetPlaceholder(index int) string {
+	return "?"
+}
+
+// GetMaxParameters returns 999, the default limit of the SQLite3 versions older than 3.32
+func (specifics *SQLite3Dialect) GetMaxParameters() int {
+	return 999
+}
+
+// GetInsertedIDs returns the rowids of the rows inserted, a sentence inserts its rows with consecutive
+// rowids ending in the last insert rowid because the database is locked while it runs
+func (specifics *SQLite3Dialect) GetInsertedIDs(lastInsertID int64, rows int) ([]int64, bool) {
+	ids := make([]int64, rows)
+	for i := range ids {
+		ids[i] = lastInsertID - int64(rows-1-i)
+	}
+	return ids, true
+}
+
+// GetSQLBoolean returns 1 or 0, SQLite3 stores the booleans as integers
+func (specifics *SQLite3Dialect) GetSQLBoolean(value bool) string {
+	if value {
+		return "1"
+	}
+	return "0"
+}
+
+// GetSQLLiteral returns the literal of a value for SQLite3, times are written with the format used by the driver
+func (specifics *SQLite3Dialect) GetSQLLiteral(value interface{}) (string, error) {
+	return sqlLiteral(specifics, value, "2006-01-02 15:04:05.999999999-07:00")
+}
+
+// GetSQLUpsert returns the ON CONFLICT clause for SQLite3, it requires SQLite 3.24 or later
+func (specifics *SQLite3Dialect) GetSQLUpsert(conflictColumns []string, updateColumns []string) string {
+	return upsertOnConflict(specifics, conflictColumns, updateColumns)
+}
+
+// GetSQLDropTable returns the DROP TABLE sentence for SQLite3
+func (specifics *SQLite3Dialect) GetSQLDropTable(tableName string, ifExists bool) string {
+	return dropTable(specifics, tableName, ifExists)
+}
+
+// GetSQLRowID returns rowid, the last insert id of SQLite3 is the rowid of the row inserted
+func (specifics *SQLite3Dialect) GetSQLRowID() string {
+	return "rowid"
+}
+
+// GetSQLReturning returns an empty string, generated columns are read using the last insert rowid
+func (specifics *SQLite3Dialect) GetSQLReturning(columns []string) string {
+	return ""
+}
+
+// GetSQLLimitOffset returns the LIMIT and OFFSET clauses for SQLite3, OFFSET requires a LIMIT so -1 (no limit) is used
+func (specifics *SQLite3Dialect) GetSQLLimitOffset(limit int, offset int) string {
+	if limit <= 0 && offset <= 0 {
+		return ""
+	}
+	if limit <= 0 {
+		limit = -1
+	}
+	sql := " LIMIT " + strconv.Itoa(limit)
+	if offset > 0 {
+		sql += " OFFSET " + strconv.Itoa(offset)
+	}
+	return sql
+}
+
+// GetSQLJSONPath returns the value found in the path of a JSON column using json_extract, numeric elements are array indexes.
+// json_extract keeps the type of the JSON value, so the value compared is not used
+func (specifics *SQLite3Dialect) GetSQLJSONPath(column string, path []string, value interface{}) string {
+	jsonPath := "$"
+	for _, element := range path {
+		if _, err := strconv.Atoi(element); err == nil {
+			jsonPath += "[" + element + "]"
+		} else {
+			jsonPath += "." + element
+		}
+	}
+	return "json_extract(" + column + ", '" + jsonPath + "')"
+}
+
+// GetTableSchema reads the structure of a table using the pragma functions of SQLite3
+func (specifics *SQLite3Dialect) GetTableSchema(ctx context.Context, db sqlx.QueryerContext, tableName string) (schema models.TableSchema, err error) {
+	schema.Name = tableName
+
+	rows, err := db.QueryxContext(ctx, "SELECT name, type, COALESCE(dflt_value, ''), pk FROM pragma_table_info(?)", tableName)
+	if err != nil {
+		return schema, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var column models.ColumnSchema
+		var pk int
+		if err = rows.Scan(&column.Name, &column.Type, &column.Default, &pk); err != nil {
+			return schema, err
+		}
+		schema.Columns = append(schema.Columns, column)
+		if pk > 0 {
+			schema.PrimaryKeys = append(schema.PrimaryKeys, column.Name)
+		}
+	}
+	if err = rows.Err(); err != nil {
+		return schema, err
+	}
+
+	// Unique constraints are the indexes created by the table definition (origin 'u'), only one column constraints are used
+	if schema.Uniques, err = queryStrings(ctx, db, "SELECT ii.name FROM pragma_index_list(?) il, pragma_index_info(il.name) ii "+
+		"WHERE il.origin = 'u' AND (SELECT COUNT(*) FROM pragma_index_info(il.name)) = 1", tableName); err != nil {
+		return schema, err
+	}
+	if schema.ForeignKeys, err = queryStrings(ctx, db, "SELECT \"from\" FROM pragma_foreign_key_list(?)", tableName); err != nil {
+		return schema, err
+	}
+	schema.Indexes, err = queryStrings(ctx, db, "SELECT name FROM pragma_index_list(?) WHERE origin = 'c'", tableName)
+	return schema, err
+}
+
+// GetTables returns the tables of the database, the internal tables of SQLite3 are not included
+func (specifics *SQLite3Dialect) GetTables(ctx context.Context, db sqlx.QueryerContext) ([]string, error) {
+	return queryStrings(ctx, db, "SELECT name FROM sqlite_master WHERE type = 'table' AND name NOT LIKE 'sqlite_%' ORDER BY name")
+}
+
+// GetSQLAlterTable returns the ALTER TABLE sentences for SQLite3. SQLite3 can only add columns without
+// primary key, unique or foreign key constraints, with a constant default value and nullable unless they
+// have a default value, otherwise the table must be rebuilt
+func (specifics *SQLite3Dialect) GetSQLAlterTable(tableName string, diff models.TableDiff) ([]string, bool, error) {
+	if diff.PrimaryKeyChanged || len(diff.Uniques) > 0 || len(diff.ForeignKeys) > 0 {
+		return nil, false, nil
+	}
+	sentences := make([]string, 0)
+	for _, column := range diff.Columns {
+		if column.PrimaryKey || column.Unique || column.ForeignKey.IsForeignKey || strings.ContainsAny(column.Default, "()") ||
+			strings.HasPrefix(strings.ToUpper(column.Default), "CURRENT_") || (column.NotNull && column.Default == "") {
+			return nil, false, nil
+		}
+		sqlColumn, _, _, err := specifics.GetSQLCreateTableColumn(column)
+		if err != nil {
+			return nil, false, err
+		}
+		sentences = append(sentences, "ALTER TABLE "+specifics.QuoteIdentifier(tableName)+" ADD COLUMN "+sqlColumn[:len(sqlColumn)-1])
+	}
+	return sentences, true, nil
+}
+
+// GetSQLForeignKeys returns the pragma used to enable or disable the foreign keys in SQLite3
+func (specifics *SQLite3Dialect) GetSQLForeignKeys(enabled bool) string {
+	if enabled {
+		return "PRAGMA foreign_keys = ON"
+	}
+	return "PRAGMA foreign_keys = OFF"
+}
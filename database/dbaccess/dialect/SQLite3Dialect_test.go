@@ -1,8 +1,10 @@
 package dialect
 
 import (
+	"database/sql"
 	"reflect"
 	"testing"
+	"time"
 
 	"github.com/plopezm/goedb/database/models"
 )
@@ -31,7 +33,7 @@ func TestSQLiteSpecifics_GetSQLCreateTableColumn(t *testing.T) {
 					AutoIncrement: true,
 				},
 			},
-			wantSQLColumnLine: "PKColumn BIGINT PRIMARY KEY AUTOINCREMENT,",
+			wantSQLColumnLine: `"PKColumn" BIGINT PRIMARY KEY AUTOINCREMENT,`,
 			wantPrimaryKey:    "",
 			wantConstraints:   "",
 		},
@@ -44,8 +46,8 @@ func TestSQLiteSpecifics_GetSQLCreateTableColumn(t *testing.T) {
 					ColumnType: reflect.Int,
 				},
 			},
-			wantSQLColumnLine: "PKColumn INTEGER,",
-			wantPrimaryKey:    "PKColumn,",
+			wantSQLColumnLine: `"PKColumn" INTEGER,`,
+			wantPrimaryKey:    `"PKColumn",`,
 			wantConstraints:   "",
 		},
 		{
@@ -58,9 +60,9 @@ func TestSQLiteSpecifics_GetSQLCreateTableColumn(t *testing.T) {
 					ForeignKey: models.ForeignKey{IsForeignKey: true, ForeignKeyTableReference: "OtherTable", ForeignKeyColumnReference: "OtherTablePK"},
 				},
 			},
-			wantSQLColumnLine: "PKColumn INTEGER,",
-			wantPrimaryKey:    "PKColumn,",
-			wantConstraints:   ", FOREIGN KEY (PKColumn) REFERENCES OtherTable(OtherTablePK) ON DELETE CASCADE",
+			wantSQLColumnLine: `"PKColumn" INTEGER,`,
+			wantPrimaryKey:    `"PKColumn",`,
+			wantConstraints:   `, FOREIGN KEY ("PKColumn") REFERENCES "OtherTable"("OtherTablePK") ON DELETE CASCADE`,
 		},
 		{
 			name: "TestErrorTypeNotFound",
@@ -83,7 +85,7 @@ func TestSQLiteSpecifics_GetSQLCreateTableColumn(t *testing.T) {
 					Unique:     true,
 				},
 			},
-			wantSQLColumnLine: "UniqueColumnString VARCHAR UNIQUE,",
+			wantSQLColumnLine: `"UniqueColumnString" VARCHAR UNIQUE,`,
 		},
 		{
 			name: "TestNormalStringColumn",
@@ -93,7 +95,7 @@ func TestSQLiteSpecifics_GetSQLCreateTableColumn(t *testing.T) {
 					ColumnType: reflect.String,
 				},
 			},
-			wantSQLColumnLine: "NormalColumnString VARCHAR,",
+			wantSQLColumnLine: `"NormalColumnString" VARCHAR,`,
 		},
 		{
 			name: "TestNormalStringColumn",
@@ -103,7 +105,7 @@ func TestSQLiteSpecifics_GetSQLCreateTableColumn(t *testing.T) {
 					ColumnType: reflect.Float64,
 				},
 			},
-			wantSQLColumnLine: "NormalColumnString FLOAT,",
+			wantSQLColumnLine: `"NormalColumnString" FLOAT,`,
 		},
 		{
 			name: "TestNormalStringColumn",
@@ -113,7 +115,85 @@ func TestSQLiteSpecifics_GetSQLCreateTableColumn(t *testing.T) {
 					ColumnType: reflect.Bool,
 				},
 			},
-			wantSQLColumnLine: "NormalColumnString BOOLEAN,",
+			wantSQLColumnLine: `"NormalColumnString" BOOLEAN,`,
+		},
+		{
+			name: "TestColumnWithDefault",
+			args: args{
+				value: models.Column{
+					Title:      "DefaultColumnString",
+					ColumnType: reflect.String,
+					Default:    "'active'",
+				},
+			},
+			wantSQLColumnLine: `"DefaultColumnString" VARCHAR DEFAULT 'active',`,
+		},
+		{
+			name: "TestNotNullColumn",
+			args: args{
+				value: models.Column{
+					Title:      "NotNullColumnString",
+					ColumnType: reflect.String,
+					NotNull:    true,
+					Unique:     true,
+				},
+			},
+			wantSQLColumnLine: `"NotNullColumnString" VARCHAR NOT NULL UNIQUE,`,
+		},
+		{
+			name: "TestNullableColumn",
+			args: args{
+				value: models.Column{
+					Title:      "NullableColumnInt",
+					ColumnType: reflect.Int64,
+				},
+			},
+			wantSQLColumnLine: `"NullableColumnInt" BIGINT,`,
+		},
+		{
+			name: "TestColumnWithSQLType",
+			args: args{
+				value: models.Column{
+					Title:      "Amount",
+					ColumnType: reflect.Int64,
+					SQLType:    "NUMERIC(12,2)",
+					NotNull:    true,
+				},
+			},
+			wantSQLColumnLine: `"Amount" NUMERIC(12,2) NOT NULL,`,
+		},
+		{
+			name: "TestCustomStructWithoutSQLType",
+			args: args{
+				value: models.Column{
+					Title:      "ID",
+					ColumnType: reflect.Struct,
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "TestTimeColumn",
+			args: args{
+				value: models.Column{
+					Title:          "CreatedAt",
+					ColumnType:     reflect.Struct,
+					ColumnTypeName: "Time",
+					IsTime:         true,
+				},
+			},
+			wantSQLColumnLine: `"CreatedAt" DATETIME,`,
+		},
+		{
+			name: "TestJSONColumn",
+			args: args{
+				value: models.Column{
+					Title:      "Settings",
+					ColumnType: reflect.Map,
+					IsJSON:     true,
+				},
+			},
+			wantSQLColumnLine: `"Settings" TEXT,`,
 		},
 	}
 	for _, tt := range tests {
@@ -136,3 +216,210 @@ func TestSQLiteSpecifics_GetSQLCreateTableColumn(t *testing.T) {
 		})
 	}
 }
+
+func TestSQLite3Dialect_QuoteIdentifier(t *testing.T) {
+	tests := []struct {
+		name       string
+		identifier string
+		want       string
+	}{
+		{name: "ReservedWord", identifier: "Order", want: `"Order"`},
+		{name: "EmbeddedQuote", identifier: `Or"der`, want: `"Or""der"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			specifics := &SQLite3Dialect{}
+			if got := specifics.QuoteIdentifier(tt.identifier); got != tt.want {
+				t.Errorf("SQLite3Dialect.QuoteIdentifier() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSQLite3Dialect_GetSQLReturning(t *testing.T) {
+	specifics := &SQLite3Dialect{}
+	if got := specifics.GetSQLReturning([]string{"ID", "Status"}); got != "" {
+		t.Errorf("SQLite3Dialect.GetSQLReturning() = %v, want empty", got)
+	}
+}
+
+func TestSQLite3Dialect_GetSQLLimitOffset(t *testing.T) {
+	tests := []struct {
+		name   string
+		limit  int
+		offset int
+		want   string
+	}{
+		{name: "LimitAndOffset", limit: 20, offset: 40, want: " LIMIT 20 OFFSET 40"},
+		{name: "OnlyLimit", limit: 20, want: " LIMIT 20"},
+		{name: "OnlyOffset", offset: 40, want: " LIMIT -1 OFFSET 40"},
+		{name: "None", want: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			specifics := &SQLite3Dialect{}
+			if got := specifics.GetSQLLimitOffset(tt.limit, tt.offset); got != tt.want {
+				t.Errorf("SQLite3Dialect.GetSQLLimitOffset() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSQLite3Dialect_GetSQLJSONPath(t *testing.T) {
+	tests := []struct {
+		name  string
+		path  []string
+		value interface{}
+		want  string
+	}{
+		{name: "Object", path: []string{"address", "city"}, want: "json_extract(soldier.Settings, '$.address.city')"},
+		{name: "Array", path: []string{"tags", "0"}, want: "json_extract(soldier.Settings, '$.tags[0]')"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			specifics := &SQLite3Dialect{}
+			if got := specifics.GetSQLJSONPath("soldier.Settings", tt.path, tt.value); got != tt.want {
+				t.Errorf("SQLite3Dialect.GetSQLJSONPath() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSQLite3Dialect_GetSQLAlterTable(t *testing.T) {
+	tests := []struct {
+		name    string
+		diff    models.TableDiff
+		want    []string
+		wantOk  bool
+		wantErr bool
+	}{
+		{
+			name: "NewColumns",
+			diff: models.TableDiff{
+				Columns: []models.Column{
+					{Title: "Status", ColumnType: reflect.String, Default: "'active'"},
+					{Title: "Amount", ColumnType: reflect.Float64},
+				},
+			},
+			want: []string{
+				`ALTER TABLE "Table1" ADD COLUMN "Status" VARCHAR DEFAULT 'active'`,
+				`ALTER TABLE "Table1" ADD COLUMN "Amount" FLOAT`,
+			},
+			wantOk: true,
+		},
+		{
+			name: "NewUniqueColumn",
+			diff: models.TableDiff{Columns: []models.Column{{Title: "Name", ColumnType: reflect.String, Unique: true}}},
+		},
+		{
+			name: "NewColumnWithExpressionDefault",
+			diff: models.TableDiff{Columns: []models.Column{{Title: "Created", ColumnType: reflect.String, Default: "CURRENT_TIMESTAMP"}}},
+		},
+		{
+			name: "NewNotNullColumnWithoutDefault",
+			diff: models.TableDiff{Columns: []models.Column{{Title: "Email", ColumnType: reflect.String, NotNull: true}}},
+		},
+		{
+			name: "NewForeignKey",
+			diff: models.TableDiff{ForeignKeys: []models.Column{{Title: "TeamID", ColumnType: reflect.Int, ForeignKey: models.ForeignKey{IsForeignKey: true, ForeignKeyTableReference: "Team", ForeignKeyColumnReference: "ID"}}}},
+		},
+		{
+			name: "PrimaryKeyChanged",
+			diff: models.TableDiff{PrimaryKeyChanged: true},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			specifics := &SQLite3Dialect{}
+			got, ok, err := specifics.GetSQLAlterTable("Table1", tt.diff)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("SQLite3Dialect.GetSQLAlterTable() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if ok != tt.wantOk {
+				t.Errorf("SQLite3Dialect.GetSQLAlterTable() ok = %v, want %v", ok, tt.wantOk)
+			}
+			if tt.wantOk && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("SQLite3Dialect.GetSQLAlterTable() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSQLite3Dialect_GetPlaceholder(t *testing.T) {
+	specifics := &SQLite3Dialect{}
+	if got := specifics.GetPlaceholder(2); got != "?" {
+		t.Errorf("SQLite3Dialect.GetPlaceholder() = %v, want %v", got, "?")
+	}
+}
+
+func TestSQLite3Dialect_GetSQLLiteral(t *testing.T) {
+	created := time.Date(2020, 5, 17, 22, 30, 0, 0, time.FixedZone("CEST", 2*60*60))
+	tests := []struct {
+		name    string
+		value   interface{}
+		want    string
+		wantErr bool
+	}{
+		{name: "Nil", value: nil, want: "NULL"},
+		{name: "True", value: true, want: "1"},
+		{name: "False", value: false, want: "0"},
+		{name: "Int", value: int64(-42), want: "-42"},
+		{name: "Float", value: 1.5, want: "1.5"},
+		{name: "String", value: "O'Brien", want: "'O''Brien'"},
+		{name: "Time", value: created, want: "'2020-05-17 20:30:00+00:00'"},
+		{name: "Valuer", value: sql.NullString{String: "Ryan", Valid: true}, want: "'Ryan'"},
+		{name: "NilPointer", value: (*string)(nil), want: "NULL"},
+		{name: "Unsupported", value: []int{1}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			specifics := &SQLite3Dialect{}
+			got, err := specifics.GetSQLLiteral(tt.value)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("SQLite3Dialect.GetSQLLiteral() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("SQLite3Dialect.GetSQLLiteral() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSQLite3Dialect_GetSQLUpsert(t *testing.T) {
+	tests := []struct {
+		name          string
+		updateColumns []string
+		want          string
+	}{
+		{name: "Update", updateColumns: []string{"Name", "Status"}, want: ` ON CONFLICT ("ID") DO UPDATE SET "Name"=excluded."Name","Status"=excluded."Status"`},
+		{name: "Nothing", want: ` ON CONFLICT ("ID") DO NOTHING`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			specifics := &SQLite3Dialect{}
+			if got := specifics.GetSQLUpsert([]string{"ID"}, tt.updateColumns); got != tt.want {
+				t.Errorf("SQLite3Dialect.GetSQLUpsert() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSQLite3Dialect_GetSQLDropTable(t *testing.T) {
+	specifics := &SQLite3Dialect{}
+	if got := specifics.GetSQLDropTable("Table1", false); got != `DROP TABLE "Table1"` {
+		t.Errorf("SQLite3Dialect.GetSQLDropTable() = %v, want %v", got, `DROP TABLE "Table1"`)
+	}
+	if got := specifics.GetSQLDropTable("Table1", true); got != `DROP TABLE IF EXISTS "Table1"` {
+		t.Errorf("SQLite3Dialect.GetSQLDropTable() = %v, want %v", got, `DROP TABLE IF EXISTS "Table1"`)
+	}
+}
+
+func TestSQLite3Dialect_GetInsertedIDs(t *testing.T) {
+	specifics := &SQLite3Dialect{}
+	ids, ok := specifics.GetInsertedIDs(10, 3)
+	if !ok || !reflect.DeepEqual(ids, []int64{8, 9, 10}) {
+		t.Errorf("SQLite3Dialect.GetInsertedIDs() = %v, %v, want [8 9 10], true", ids, ok)
+	}
+}
@@ -0,0 +1,64 @@
+package dialect
+
+import (
+	"context"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/plopezm/goedb/database/models"
+)
+
+// queryStrings returns the first column of each row found by the query
+func queryStrings(ctx context.Context, db sqlx.QueryerContext, query string, args ...interface{}) ([]string, error) {
+	rows, err := db.QueryxContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	values := make([]string, 0)
+	for rows.Next() {
+		var value string
+		if err = rows.Scan(&value); err != nil {
+			return nil, err
+		}
+		values = append(values, value)
+	}
+	return values, rows.Err()
+}
+
+// informationSchema reads the columns and constraints of a table from information_schema, it is used by Postgresql and MySQL.
+// currentSchema is the SQL expression of the schema of the connection and typeColumn the column of information_schema.columns
+// with the type of each column. The indexes are not read, they are not part of information_schema
+func informationSchema(ctx context.Context, db sqlx.QueryerContext, specifics Dialect, tableName string, currentSchema string, typeColumn string) (schema models.TableSchema, err error) {
+	schema.Name = tableName
+
+	rows, err := db.QueryxContext(ctx, "SELECT column_name, "+typeColumn+", COALESCE(column_default, '') FROM information_schema.columns "+
+		"WHERE table_schema = "+currentSchema+" AND table_name = "+specifics.GetPlaceholder(1)+" ORDER BY ordinal_position", tableName)
+	if err != nil {
+		return schema, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var column models.ColumnSchema
+		if err = rows.Scan(&column.Name, &column.Type, &column.Default); err != nil {
+			return schema, err
+		}
+		schema.Columns = append(schema.Columns, column)
+	}
+	if err = rows.Err(); err != nil {
+		return schema, err
+	}
+
+	constraintColumns := func(constraintType string) ([]string, error) {
+		return queryStrings(ctx, db, "SELECT kcu.column_name FROM information_schema.table_constraints tc "+
+			"JOIN information_schema.key_column_usage kcu ON tc.constraint_schema = kcu.constraint_schema AND tc.constraint_name = kcu.constraint_name AND tc.table_name = kcu.table_name "+
+			"WHERE tc.table_schema = "+currentSchema+" AND tc.table_name = "+specifics.GetPlaceholder(1)+" AND tc.constraint_type = "+specifics.GetPlaceholder(2), tableName, constraintType)
+	}
+	if schema.PrimaryKeys, err = constraintColumns("PRIMARY KEY"); err != nil {
+		return schema, err
+	}
+	if schema.Uniques, err = constraintColumns("UNIQUE"); err != nil {
+		return schema, err
+	}
+	schema.ForeignKeys, err = constraintColumns("FOREIGN KEY")
+	return schema, err
+}
@@ -0,0 +1,264 @@
+package migrations
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// TableName is the table where the applied versions are recorded
+const TableName = "goedb_schema_migrations"
+
+// Migration is a versioned change of the database. Up applies the change and Down reverts it,
+// Down can be nil if the migration cannot be rolled back
+type Migration struct {
+	Version int64
+	Name    string
+	Up      func(tx *sqlx.Tx) error
+	Down    func(tx *sqlx.Tx) error
+}
+
+// Status is the state of a migration in a database
+type Status struct {
+	Version   int64
+	Name      string
+	Applied   bool
+	AppliedAt time.Time
+}
+
+var registry = struct {
+	sync.Mutex
+	migrations []Migration
+}{}
+
+// Register adds migrations written in Go to the library, they are applied together with the migration files of each datasource
+func Register(migrations ...Migration) {
+	registry.Lock()
+	defer registry.Unlock()
+	registry.migrations = append(registry.migrations, migrations...)
+}
+
+// Registered returns the migrations added with Register
+func Registered() []Migration {
+	registry.Lock()
+	defer registry.Unlock()
+	return append([]Migration{}, registry.migrations...)
+}
+
+var migrationFile = regexp.MustCompile(`^(\d+)_(.+)\.(up|down)\.sql$`)
+
+// FromDir reads the migration files of a directory. Files must be named <version>_<name>.up.sql and
+// <version>_<name>.down.sql, the down file is optional. The content of each file is run as a single sentence
+func FromDir(dir string) ([]Migration, error) {
+	files, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	found := make(map[int64]*Migration)
+	for _, file := range files {
+		parts := migrationFile.FindStringSubmatch(file.Name())
+		if file.IsDir() || parts == nil {
+			continue
+		}
+		version, err := strconv.ParseInt(parts[1], 10, 64)
+		if err != nil {
+			return nil, err
+		}
+		content, err := ioutil.ReadFile(filepath.Join(dir, file.Name()))
+		if err != nil {
+			return nil, err
+		}
+		migration, ok := found[version]
+		if !ok {
+			migration = &Migration{Version: version, Name: parts[2]}
+			found[version] = migration
+		} else if migration.Name != parts[2] {
+			return nil, errors.New("Migration version " + parts[1] + " is duplicated in " + dir)
+		}
+		if parts[3] == "up" {
+			migration.Up = execSQL(string(content))
+		} else {
+			migration.Down = execSQL(string(content))
+		}
+	}
+	migrations := make([]Migration, 0, len(found))
+	for _, migration := range found {
+		if migration.Up == nil {
+			return nil, errors.New("Migration " + strconv.FormatInt(migration.Version, 10) + "_" + migration.Name + " has no up file")
+		}
+		migrations = append(migrations, *migration)
+	}
+	sortMigrations(migrations)
+	return migrations, nil
+}
+
+func execSQL(sql string) func(tx *sqlx.Tx) error {
+	return func(tx *sqlx.Tx) error {
+		_, err := tx.Exec(sql)
+		return err
+	}
+}
+
+func sortMigrations(migrations []Migration) {
+	sort.Slice(migrations, func(i, j int) bool {
+		return migrations[i].Version < migrations[j].Version
+	})
+}
+
+// Migrator applies a set of migrations to a database, the versions applied are stored in goedb_schema_migrations
+type Migrator struct {
+	db         *sqlx.DB
+	migrations []Migration
+}
+
+// NewMigrator returns a migrator for the database, the migrations are sorted by version and
+// an error is returned if a version is duplicated
+func NewMigrator(db *sqlx.DB, migrations []Migration) (*Migrator, error) {
+	sorted := append([]Migration{}, migrations...)
+	sortMigrations(sorted)
+	for i := 1; i < len(sorted); i++ {
+		if sorted[i].Version == sorted[i-1].Version {
+			return nil, errors.New("Migration version " + strconv.FormatInt(sorted[i].Version, 10) + " is duplicated")
+		}
+	}
+	return &Migrator{db: db, migrations: sorted}, nil
+}
+
+// Up applies the migrations not applied yet in version order, each one in its own transaction
+func (migrator *Migrator) Up(ctx context.Context) error {
+	applied, err := migrator.applied(ctx)
+	if err != nil {
+		return err
+	}
+	for _, migration := range migrator.migrations {
+		if _, ok := applied[migration.Version]; ok {
+			continue
+		}
+		insert := migrator.db.Rebind("INSERT INTO " + TableName + " (version, name, applied_at) VALUES (?, ?, ?)")
+		err = migrator.run(ctx, migration, migration.Up, insert, migration.Version, migration.Name, time.Now().UTC())
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Down reverts the last steps migrations applied, in reverse version order
+func (migrator *Migrator) Down(ctx context.Context, steps int) error {
+	if steps < 0 {
+		return errors.New("The number of steps to roll back cannot be negative")
+	}
+	applied, err := migrator.applied(ctx)
+	if err != nil {
+		return err
+	}
+	versions := make([]int64, 0, len(applied))
+	for version := range applied {
+		versions = append(versions, version)
+	}
+	sort.Slice(versions, func(i, j int) bool { return versions[i] > versions[j] })
+	if steps > len(versions) {
+		steps = len(versions)
+	}
+
+	for _, version := range versions[:steps] {
+		migration, ok := migrator.find(version)
+		if !ok {
+			return errors.New("Migration " + strconv.FormatInt(version, 10) + " is applied but it is not registered")
+		}
+		if migration.Down == nil {
+			return errors.New("Migration " + strconv.FormatInt(version, 10) + "_" + migration.Name + " cannot be rolled back")
+		}
+		remove := migrator.db.Rebind("DELETE FROM " + TableName + " WHERE version = ?")
+		if err = migrator.run(ctx, migration, migration.Down, remove, version); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Status returns the state of each migration, including the versions applied that are not registered
+func (migrator *Migrator) Status(ctx context.Context) ([]Status, error) {
+	applied, err := migrator.applied(ctx)
+	if err != nil {
+		return nil, err
+	}
+	status := make([]Status, 0, len(migrator.migrations))
+	for _, migration := range migrator.migrations {
+		current := Status{Version: migration.Version, Name: migration.Name}
+		if record, ok := applied[migration.Version]; ok {
+			current.Applied = true
+			current.AppliedAt = record.AppliedAt
+			delete(applied, migration.Version)
+		}
+		status = append(status, current)
+	}
+	for _, record := range applied {
+		status = append(status, record)
+	}
+	sort.Slice(status, func(i, j int) bool { return status[i].Version < status[j].Version })
+	return status, nil
+}
+
+// run executes the migration step and the sentence that records it in the same transaction
+func (migrator *Migrator) run(ctx context.Context, migration Migration, step func(tx *sqlx.Tx) error, record string, args ...interface{}) (err error) {
+	tx, err := migrator.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if p := recover(); p != nil {
+			tx.Rollback()
+			panic(p)
+		}
+	}()
+	if err = step(tx); err != nil {
+		tx.Rollback()
+		return errors.New("Migration " + strconv.FormatInt(migration.Version, 10) + "_" + migration.Name + " failed: " + err.Error())
+	}
+	if _, err = tx.ExecContext(ctx, record, args...); err != nil {
+		tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
+
+// applied creates the migrations table if it does not exist and returns the versions applied,
+// the column types are the ones accepted by every dialect (MySQL requires the length of VARCHAR)
+func (migrator *Migrator) applied(ctx context.Context) (map[int64]Status, error) {
+	_, err := migrator.db.ExecContext(ctx, "CREATE TABLE IF NOT EXISTS "+TableName+" (version BIGINT PRIMARY KEY, name VARCHAR(255), applied_at TIMESTAMP)")
+	if err != nil {
+		return nil, err
+	}
+	rows, err := migrator.db.QueryxContext(ctx, "SELECT version, name, applied_at FROM "+TableName)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	applied := make(map[int64]Status)
+	for rows.Next() {
+		record := Status{Applied: true}
+		if err = rows.Scan(&record.Version, &record.Name, &record.AppliedAt); err != nil {
+			return nil, err
+		}
+		applied[record.Version] = record
+	}
+	return applied, rows.Err()
+}
+
+func (migrator *Migrator) find(version int64) (Migration, bool) {
+	for _, migration := range migrator.migrations {
+		if migration.Version == version {
+			return migration, true
+		}
+	}
+	return Migration{}, false
+}
@@ -0,0 +1,44 @@
+package migrations
+
+import (
+	"context"
+	"testing"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFromDir(t *testing.T) {
+	migrations, err := FromDir("../../testdata/migrations")
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(migrations))
+	assert.Equal(t, int64(1), migrations[0].Version)
+	assert.Equal(t, "create_flag", migrations[0].Name)
+	assert.NotNil(t, migrations[0].Up)
+	assert.NotNil(t, migrations[0].Down)
+	assert.Equal(t, int64(2), migrations[1].Version)
+	assert.Equal(t, "insert_flag", migrations[1].Name)
+}
+
+func TestFromDirNotFound(t *testing.T) {
+	_, err := FromDir("notfound")
+	assert.NotNil(t, err)
+}
+
+func TestNewMigrator(t *testing.T) {
+	noop := func(tx *sqlx.Tx) error { return nil }
+
+	migrator, err := NewMigrator(nil, []Migration{{Version: 3, Name: "c", Up: noop}, {Version: 1, Name: "a", Up: noop}})
+	assert.Nil(t, err)
+	assert.Equal(t, int64(1), migrator.migrations[0].Version)
+	assert.Equal(t, int64(3), migrator.migrations[1].Version)
+
+	_, err = NewMigrator(nil, []Migration{{Version: 1, Name: "a", Up: noop}, {Version: 1, Name: "b", Up: noop}})
+	assert.NotNil(t, err)
+}
+
+func TestMigrator_Down_NegativeSteps(t *testing.T) {
+	migrator, err := NewMigrator(nil, nil)
+	assert.Nil(t, err)
+	assert.NotNil(t, migrator.Down(context.Background(), -1))
+}
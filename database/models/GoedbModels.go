@@ -2,7 +2,7 @@ package models
 
 import "reflect"
 
-// Table represents the metadata of a table
+// Table represents the metadata of a table, Name is the name of the table in the database
 type Table struct {
 	Name        string
 	Columns     []Column
@@ -15,24 +15,66 @@ type PrimaryKey struct {
 	Type reflect.Kind
 }
 
-//ForeignKey contains the table and column reference of a ForeignKey
+//ForeignKey contains the table and column reference of a ForeignKey. When the column is a struct,
+//ForeignKeyField is the referenced field of the struct and ForeignKeyModel the key of its model
 type ForeignKey struct {
 	IsForeignKey              bool
 	ForeignKeyTableReference  string
 	ForeignKeyColumnReference string
+	ForeignKeyField           string
+	ForeignKeyModel           string
 }
 
-// Column represents the metadata of a column
+// Column represents the metadata of a column, Title is the name of the column in the database and Field the name of the struct field
 type Column struct {
 	Title          string
+	Field          string
 	ColumnType     reflect.Kind
 	ColumnTypeName string
 	PrimaryKey     bool
 	Unique         bool
 	ForeignKey     ForeignKey
 	AutoIncrement  bool
+	Default        string
+	SQLType        string
+	Index          bool
+	NotNull        bool
+	IsTime         bool
+	IsJSON         bool
 	IsComplex      bool
 	Ignore         bool
+	Version        bool
+	CreatedAt      bool
+	UpdatedAt      bool
+	SoftDelete     bool
+}
+
+// TableSchema represents the structure of a table found in the database, it has no columns if the table does not exist
+type TableSchema struct {
+	Name        string
+	Columns     []ColumnSchema
+	PrimaryKeys []string
+	Uniques     []string
+	ForeignKeys []string
+	Indexes     []string
+}
+
+// ColumnSchema represents a column found in the database
+type ColumnSchema struct {
+	Name    string
+	Type    string
+	Default string
+}
+
+// TableDiff contains the changes required to migrate an existing table to its model
+type TableDiff struct {
+	// Columns are the columns of the model not found in the table
+	Columns []Column
+	// Uniques are the existing columns without its unique constraint
+	Uniques []Column
+	// ForeignKeys are the existing columns without its foreign key
+	ForeignKeys       []Column
+	PrimaryKeyChanged bool
 }
 
 // Result is the result for some operation in database
@@ -0,0 +1,57 @@
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"reflect"
+)
+
+// jsonColumn reads and writes a field tagged with json as a JSON document, nil values are stored as NULL
+type jsonColumn struct {
+	value reflect.Value
+}
+
+func (c jsonColumn) Value() (driver.Value, error) {
+	switch c.value.Kind() {
+	case reflect.Ptr, reflect.Map, reflect.Slice, reflect.Interface:
+		if c.value.IsNil() {
+			return nil, nil
+		}
+	}
+	data, err := json.Marshal(c.value.Interface())
+	return string(data), err
+}
+
+func (c jsonColumn) Scan(src interface{}) error {
+	var data []byte
+	switch value := src.(type) {
+	case nil:
+		c.value.Set(reflect.Zero(c.value.Type()))
+		return nil
+	case []byte:
+		data = value
+	case string:
+		data = []byte(value)
+	default:
+		return errors.New("Cannot scan " + reflect.TypeOf(src).String() + " into a json column")
+	}
+	decoded := reflect.New(c.value.Type())
+	if err := json.Unmarshal(data, decoded.Interface()); err != nil {
+		return err
+	}
+	c.value.Set(decoded.Elem())
+	return nil
+}
+
+// isJSONField returns true if the field is tagged with json
+func isJSONField(field reflect.StructField) bool {
+	if tag, ok := field.Tag.Lookup("goedb"); ok {
+		for _, val := range splitTag(tag) {
+			if val == "json" {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,153 @@
+package models
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"unicode"
+)
+
+// NamingStrategy converts the names of the structs and their fields into the names of the tables and columns
+type NamingStrategy interface {
+	TableName(structName string) string
+	ColumnName(fieldName string) string
+}
+
+// TableNamer is implemented by the structs that set the name of their table, the name is used as it is returned
+type TableNamer interface {
+	TableName() string
+}
+
+// DefaultNaming uses the names of the structs and fields as they are
+type DefaultNaming struct{}
+
+// TableName returns the name of the struct
+func (DefaultNaming) TableName(structName string) string {
+	return structName
+}
+
+// ColumnName returns the name of the field
+func (DefaultNaming) ColumnName(fieldName string) string {
+	return fieldName
+}
+
+// SnakeCaseNaming converts the names to snake_case, e.g. TroopID is troop_id
+type SnakeCaseNaming struct{}
+
+// TableName returns the name of the struct in snake_case
+func (SnakeCaseNaming) TableName(structName string) string {
+	return toSnakeCase(structName)
+}
+
+// ColumnName returns the name of the field in snake_case
+func (SnakeCaseNaming) ColumnName(fieldName string) string {
+	return toSnakeCase(fieldName)
+}
+
+// LowerCaseNaming converts the names to lower case, e.g. TroopID is troopid
+type LowerCaseNaming struct{}
+
+// TableName returns the name of the struct in lower case
+func (LowerCaseNaming) TableName(structName string) string {
+	return strings.ToLower(structName)
+}
+
+// ColumnName returns the name of the field in lower case
+func (LowerCaseNaming) ColumnName(fieldName string) string {
+	return strings.ToLower(fieldName)
+}
+
+// PrefixNaming adds a prefix to the table names generated by another strategy
+type PrefixNaming struct {
+	Prefix string
+	Naming NamingStrategy
+}
+
+// TableName returns the table name of the strategy with the prefix
+func (naming PrefixNaming) TableName(structName string) string {
+	return naming.Prefix + naming.Naming.TableName(structName)
+}
+
+// ColumnName returns the column name of the strategy
+func (naming PrefixNaming) ColumnName(fieldName string) string {
+	return naming.Naming.ColumnName(fieldName)
+}
+
+// GetNamingStrategy returns the strategy of a datasource: "" (the Go names), "snake_case" or "lowercase".
+// If prefix is not empty, it is added to every table name
+func GetNamingStrategy(name string, prefix string) (naming NamingStrategy, err error) {
+	switch name {
+	case "":
+		naming = DefaultNaming{}
+	case "snake_case":
+		naming = SnakeCaseNaming{}
+	case "lowercase":
+		naming = LowerCaseNaming{}
+	default:
+		return nil, errors.New("Naming strategy \"" + name + "\" not supported")
+	}
+	if prefix != "" {
+		naming = PrefixNaming{Prefix: prefix, Naming: naming}
+	}
+	return naming, nil
+}
+
+// ModelKey returns the name used to store the model of a struct, it includes the package of the
+// struct so structs with the same name in different packages do not collide
+func ModelKey(typ reflect.Type) string {
+	if typ.PkgPath() == "" {
+		return typ.Name()
+	}
+	return typ.PkgPath() + "." + typ.Name()
+}
+
+// getTableName returns the name of the table of a struct: the one returned by its TableName method,
+// the one set with the table tag in a blank field (_ struct{} `goedb:"table=name"`) or the one generated by the strategy
+func getTableName(typ reflect.Type, naming NamingStrategy) string {
+	if namer, ok := reflect.New(typ).Interface().(TableNamer); ok {
+		return namer.TableName()
+	}
+	for i := 0; i < typ.NumField(); i++ {
+		if name := getTagValue(typ.Field(i), "table="); name != "" {
+			return name
+		}
+	}
+	return naming.TableName(typ.Name())
+}
+
+// getColumnName returns the name of the column of a field: the one set with the column tag or the one generated by the strategy
+func getColumnName(field reflect.StructField, naming NamingStrategy) string {
+	if name := getTagValue(field, "column="); name != "" {
+		return name
+	}
+	return naming.ColumnName(field.Name)
+}
+
+// getTagValue returns the value of a goedb tag attribute with the format prefix=value
+func getTagValue(field reflect.StructField, prefix string) string {
+	if tag, ok := field.Tag.Lookup("goedb"); ok {
+		for _, val := range splitTag(tag) {
+			if strings.HasPrefix(val, prefix) {
+				return val[len(prefix):]
+			}
+		}
+	}
+	return ""
+}
+
+// toSnakeCase converts a Go name to snake_case, keeping acronyms together (HTTPServer is http_server)
+func toSnakeCase(name string) string {
+	runes := []rune(name)
+	var snake strings.Builder
+	for i, r := range runes {
+		if unicode.IsUpper(r) {
+			if i > 0 && (unicode.IsLower(runes[i-1]) || unicode.IsDigit(runes[i-1]) ||
+				(i+1 < len(runes) && unicode.IsLower(runes[i+1]) && unicode.IsUpper(runes[i-1]))) {
+				snake.WriteRune('_')
+			}
+			r = unicode.ToLower(r)
+		}
+		snake.WriteRune(r)
+	}
+	return snake.String()
+}
@@ -1,6 +1,8 @@
 package models
 
 import (
+	"database/sql"
+	"database/sql/driver"
 	"errors"
 	"reflect"
 	"strings"
@@ -33,9 +35,31 @@ func GetValue(i interface{}) reflect.Value {
 	return val
 }
 
+// splitTag splits the attributes of a goedb tag, the commas inside parentheses (e.g. type=NUMERIC(12,2)) are not separators
+func splitTag(tag string) []string {
+	params := make([]string, 0)
+	depth, start := 0, 0
+	for i, c := range tag {
+		switch c {
+		case '(':
+			depth++
+		case ')':
+			if depth > 0 {
+				depth--
+			}
+		case ',':
+			if depth == 0 {
+				params = append(params, tag[start:i])
+				start = i + 1
+			}
+		}
+	}
+	return append(params, tag[start:])
+}
+
 func tagAttributeExists(tag reflect.StructTag, attribute string) bool {
 	if tag, ok := tag.Lookup("goedb"); ok {
-		params := strings.Split(tag, ",")
+		params := splitTag(tag)
 		for _, val := range params {
 			if strings.Contains(attribute, val) {
 				return true
@@ -50,7 +74,7 @@ func GetGoedbTagTypeAndValueOfForeignKeyReference(instanceType reflect.Type, ins
 	for i := 0; i < instanceType.NumField(); i++ {
 		field := instanceType.Field(i)
 		value := instanceValue.Field(i)
-		if tagAttributeExists(field.Tag, goedbTag) && foreignKeyReference.ForeignKeyColumnReference == field.Name {
+		if tagAttributeExists(field.Tag, goedbTag) && foreignKeyReference.ForeignKeyField == field.Name {
 			return field.Type, value, nil
 		}
 	}
@@ -79,9 +103,62 @@ func GetGoedbTagTypeAndValueOfIndexField(instanceType reflect.Type, instanceValu
 }
 */
 
-func processColumnType(column *Column, columnType reflect.Type, columnValue reflect.Value) error {
+// nullTypes are the nullable types of database/sql and the kind of the value they contain, sql.NullTime contains a time
+var nullTypes = map[reflect.Type]reflect.Kind{
+	reflect.TypeOf(sql.NullString{}):  reflect.String,
+	reflect.TypeOf(sql.NullInt64{}):   reflect.Int64,
+	reflect.TypeOf(sql.NullInt32{}):   reflect.Int32,
+	reflect.TypeOf(sql.NullInt16{}):   reflect.Int16,
+	reflect.TypeOf(sql.NullByte{}):    reflect.Uint8,
+	reflect.TypeOf(sql.NullFloat64{}): reflect.Float64,
+	reflect.TypeOf(sql.NullBool{}):    reflect.Bool,
+	reflect.TypeOf(sql.NullTime{}):    reflect.Struct,
+}
+
+var scannerType = reflect.TypeOf((*sql.Scanner)(nil)).Elem()
+var valuerType = reflect.TypeOf((*driver.Valuer)(nil)).Elem()
+
+// IsCustomType returns true if the type implements sql.Scanner and driver.Valuer, its values are read and written using them
+func IsCustomType(typ reflect.Type) bool {
+	ptr := reflect.PtrTo(typ)
+	return ptr.Implements(scannerType) && (typ.Implements(valuerType) || ptr.Implements(valuerType))
+}
+
+// isColumnStruct returns true for the structs stored in a single column instead of as a relation
+func isColumnStruct(typ reflect.Type) bool {
+	_, ok := nullTypes[typ]
+	return ok || IsTime(typ) || IsCustomType(typ)
+}
+
+func processColumnType(column *Column, columnType reflect.Type, columnValue reflect.Value, naming NamingStrategy) error {
+	if column.IsJSON {
+		column.ColumnTypeName = columnType.Name()
+		column.ColumnType = columnType.Kind()
+		return nil
+	}
 
+	if columnType.Kind() == reflect.Ptr {
+		columnType = columnType.Elem()
+		if columnType.Kind() == reflect.Struct && !isColumnStruct(columnType) {
+			column.ColumnTypeName = columnType.Name()
+			return errors.New("Pointers to structs are not supported")
+		}
+	}
 	column.ColumnTypeName = columnType.Name()
+	if kind, ok := nullTypes[columnType]; ok {
+		column.ColumnType = kind
+		column.IsTime = kind == reflect.Struct
+		return nil
+	}
+	if IsTime(columnType) {
+		column.ColumnType = reflect.Struct
+		column.IsTime = true
+		return nil
+	}
+	if IsCustomType(columnType) {
+		column.ColumnType = columnType.Kind()
+		return nil
+	}
 	if columnType.Kind() != reflect.Struct {
 		column.ColumnType = columnType.Kind()
 		return nil
@@ -93,24 +170,40 @@ func processColumnType(column *Column, columnType reflect.Type, columnValue refl
 
 	column.ColumnType = primaryKeyType.Kind()
 	column.IsComplex = true
+	//The referenced table and column are named as in the model of the referenced struct
+	referencedField, _ := columnType.FieldByName(column.ForeignKey.ForeignKeyField)
+	column.ForeignKey.ForeignKeyTableReference = getTableName(columnType, naming)
+	column.ForeignKey.ForeignKeyColumnReference = getColumnName(referencedField, naming)
+	column.ForeignKey.ForeignKeyModel = ModelKey(columnType)
 	return nil
 }
 
-// ParseModel generates a GoedbTable, the model of a struct
+// ParseModel generates a GoedbTable, the model of a struct, using the names of the struct and its fields.
+// The fields whose type is not supported are kept without type, ParseModelWithNaming returns their errors
 func ParseModel(entity interface{}) Table {
+	table, _ := ParseModelWithNaming(entity, DefaultNaming{})
+	return table
+}
+
+// ParseModelWithNaming generates a GoedbTable, the model of a struct, the names of the table and columns
+// not set with TableName or the table and column tags are generated by the naming strategy.
+// An error is returned if the type of a field that is not ignored is not supported
+func ParseModelWithNaming(entity interface{}, naming NamingStrategy) (Table, error) {
 	entityType := GetType(entity)
 	entityValue := GetValue(entity)
 
 	table := Table{}
-	table.Name = entityType.Name()
+	table.Name = getTableName(entityType, naming)
 	table.Columns = make([]Column, 0)
 
 	for i := 0; i < entityType.NumField(); i++ {
 		tablecol := Column{}
-		tablecol.Title = entityType.Field(i).Name
+		tablecol.Title = getColumnName(entityType.Field(i), naming)
+		tablecol.Field = entityType.Field(i).Name
+		tablecol.Ignore = isTransientField(entityType.Field(i))
 
 		if tag, ok := entityType.Field(i).Tag.Lookup("goedb"); ok {
-			params := strings.Split(tag, ",")
+			params := splitTag(tag)
 			for _, val := range params {
 				switch val {
 				case "pk":
@@ -123,7 +216,27 @@ func ParseModel(entity interface{}) Table {
 					tablecol.Unique = true
 				case "ignore":
 					tablecol.Ignore = true
+				case "index":
+					tablecol.Index = true
+				case "notnull":
+					tablecol.NotNull = true
+				case "json":
+					tablecol.IsJSON = true
+				case "version":
+					tablecol.Version = true
+				case "createdAt":
+					tablecol.CreatedAt = true
+				case "updatedAt":
+					tablecol.UpdatedAt = true
+				case "softDelete":
+					tablecol.SoftDelete = true
 				default:
+					if strings.HasPrefix(val, "default=") {
+						tablecol.Default = val[8:]
+					}
+					if strings.HasPrefix(val, "type=") {
+						tablecol.SQLType = val[5:]
+					}
 					if strings.Contains(val, "fk=") {
 						tablecol.ForeignKey.IsForeignKey = true
 						//References are received in the following format: ReferencedTable(ReferencedColumn)
@@ -131,28 +244,93 @@ func ParseModel(entity interface{}) Table {
 						fksubtags := strings.Split(fktag, "(")
 						tablecol.ForeignKey.ForeignKeyTableReference = fksubtags[0]
 						tablecol.ForeignKey.ForeignKeyColumnReference = fksubtags[1][:len(fksubtags[1])-1]
+						tablecol.ForeignKey.ForeignKeyField = tablecol.ForeignKey.ForeignKeyColumnReference
 
 					}
 				}
 			}
 		}
-		processColumnType(&tablecol, entityType.Field(i).Type, entityValue)
+		err := processColumnType(&tablecol, entityType.Field(i).Type, entityValue, naming)
+		if err != nil && !tablecol.Ignore {
+			return table, errors.New("Field " + tablecol.Field + " of " + entityType.Name() + ": " + err.Error())
+		}
+		//The struct and field referenced by a scalar foreign key are named as the struct foreign keys
+		if tablecol.ForeignKey.IsForeignKey && !tablecol.IsComplex {
+			tablecol.ForeignKey.ForeignKeyTableReference = naming.TableName(tablecol.ForeignKey.ForeignKeyTableReference)
+			tablecol.ForeignKey.ForeignKeyColumnReference = naming.ColumnName(tablecol.ForeignKey.ForeignKeyColumnReference)
+		}
 		if tablecol.PrimaryKey || tablecol.Unique {
 			table.PrimaryKeys = append(table.PrimaryKeys, PrimaryKey{Name: tablecol.Title, Type: tablecol.ColumnType})
 		}
 		table.Columns = append(table.Columns, tablecol)
 	}
-	return table
+	return table, nil
+}
+
+// GetVersionColumn returns the column used for optimistic locking, the one with the version tag
+func GetVersionColumn(table Table) (Column, bool) {
+	for _, column := range table.Columns {
+		if column.Version && !column.Ignore {
+			return column, true
+		}
+	}
+	return Column{}, false
+}
+
+// GetSoftDeleteColumn returns the timestamp or boolean column marking the rows removed, the one with the softDelete tag
+func GetSoftDeleteColumn(table Table) (Column, bool) {
+	for _, column := range table.Columns {
+		if column.SoftDelete && !column.Ignore {
+			return column, true
+		}
+	}
+	return Column{}, false
+}
+
+// IsGenerated returns true when the value of the column is generated by the database on insert,
+// this happens with autoincrement columns and with columns with a default value not set in the instance
+func IsGenerated(column Column, value reflect.Value) bool {
+	if column.AutoIncrement {
+		return true
+	}
+	if column.Default == "" || column.IsComplex {
+		return false
+	}
+	return reflect.DeepEqual(value.Interface(), reflect.Zero(value.Type()).Interface())
+}
+
+// isTransientField returns true for the fields that are never stored: the blank fields, which only carry the table tag,
+// and the embedded structs without exported fields (e.g. database.Tracked)
+func isTransientField(field reflect.StructField) bool {
+	if field.Name == "_" {
+		return true
+	}
+	if !field.Anonymous || field.Type.Kind() != reflect.Struct || isColumnStruct(field.Type) {
+		return false
+	}
+	for i := 0; i < field.Type.NumField(); i++ {
+		if field.Type.Field(i).PkgPath == "" {
+			return false
+		}
+	}
+	return true
 }
 
 func getSubStructAddresses(slice *[]interface{}, value reflect.Value) {
 	for j := 0; j < value.NumField(); j++ {
 		subField := value.Field(j)
-		if subField.Kind() == reflect.Struct {
+		if isTransientField(value.Type().Field(j)) {
+			continue
+		}
+		if isJSONField(value.Type().Field(j)) {
+			*slice = append(*slice, jsonColumn{value: subField})
+			continue
+		}
+		if subField.Kind() == reflect.Struct && !isColumnStruct(subField.Type()) {
 			getSubStructAddresses(slice, subField)
 			continue
 		}
-		*slice = append(*slice, subField.Addr().Interface())
+		*slice = append(*slice, FieldAddress(subField))
 	}
 }
 
@@ -176,18 +354,25 @@ func StructToSliceOfAddresses(structPtr interface{}) []interface{} {
 	for i := 0; i < fieldArr.NumField(); i++ {
 		f := fieldArr.Field(i)
 
-		if f.Kind() == reflect.Struct {
+		if isTransientField(fieldArr.Type().Field(i)) {
+			continue
+		}
+		if isJSONField(fieldArr.Type().Field(i)) {
+			fieldAddrArr = append(fieldAddrArr, jsonColumn{value: f})
+			continue
+		}
+		if f.Kind() == reflect.Struct && !isColumnStruct(f.Type()) {
 			getSubStructAddresses(&fieldAddrArr, f)
 			continue
 		}
-		fieldAddrArr = append(fieldAddrArr, f.Addr().Interface())
+		fieldAddrArr = append(fieldAddrArr, FieldAddress(f))
 	}
 
 	return fieldAddrArr
 }
 
 func getSubStructAddressesWithRules(slice *[]interface{}, value reflect.Value, GetModel func(name string) (Table, bool)) {
-	tablemodel, ok := GetModel(value.Type().Name())
+	tablemodel, ok := GetModel(ModelKey(value.Type()))
 	if !ok {
 		return
 	}
@@ -196,11 +381,15 @@ func getSubStructAddressesWithRules(slice *[]interface{}, value reflect.Value, G
 			continue
 		}
 		subField := value.Field(j)
-		if subField.Kind() == reflect.Struct {
+		if tablemodel.Columns[j].IsJSON {
+			*slice = append(*slice, jsonColumn{value: subField})
+			continue
+		}
+		if subField.Kind() == reflect.Struct && !isColumnStruct(subField.Type()) {
 			getSubStructAddresses(slice, subField)
 			continue
 		}
-		*slice = append(*slice, subField.Addr().Interface())
+		*slice = append(*slice, FieldAddress(subField))
 	}
 }
 
@@ -217,7 +406,7 @@ func StructToSliceOfAddressesWithRules(structPtr interface{}, GetModel func(name
 	if fieldArr.Kind() == reflect.Ptr {
 		fieldArr = fieldArr.Elem()
 	}
-	tablemodel, ok := GetModel(fieldArr.Type().Name())
+	tablemodel, ok := GetModel(ModelKey(fieldArr.Type()))
 	if !ok {
 		return nil
 	}
@@ -230,11 +419,15 @@ func StructToSliceOfAddressesWithRules(structPtr interface{}, GetModel func(name
 
 		f := fieldArr.Field(i)
 
-		if f.Kind() == reflect.Struct {
+		if tablemodel.Columns[i].IsJSON {
+			fieldAddrArr = append(fieldAddrArr, jsonColumn{value: f})
+			continue
+		}
+		if f.Kind() == reflect.Struct && !isColumnStruct(f.Type()) {
 			getSubStructAddressesWithRules(&fieldAddrArr, f, GetModel)
 			continue
 		}
-		fieldAddrArr = append(fieldAddrArr, f.Addr().Interface())
+		fieldAddrArr = append(fieldAddrArr, FieldAddress(f))
 	}
 
 	return fieldAddrArr
@@ -1,8 +1,12 @@
 package models
 
 import (
+	"database/sql"
+	"database/sql/driver"
 	"reflect"
+	"strings"
 	"testing"
+	"time"
 )
 
 func TestParseModel(t *testing.T) {
@@ -36,6 +40,7 @@ func TestParseModel(t *testing.T) {
 				Columns: []Column{
 					{
 						Title:          "ID",
+						Field:          "ID",
 						AutoIncrement:  true,
 						PrimaryKey:     true,
 						ColumnType:     reflect.Uint64,
@@ -43,6 +48,7 @@ func TestParseModel(t *testing.T) {
 					},
 					{
 						Title:          "Name",
+						Field:          "Name",
 						Unique:         true,
 						ColumnType:     reflect.String,
 						ColumnTypeName: "string",
@@ -68,20 +74,23 @@ func TestParseModel(t *testing.T) {
 				Columns: []Column{
 					{
 						Title:          "Name",
+						Field:          "Name",
 						PrimaryKey:     true,
 						ColumnType:     reflect.String,
 						ColumnTypeName: "string",
 					},
 					{
 						Title:          "TestTableName",
+						Field:          "TestTableName",
 						PrimaryKey:     true,
 						ColumnType:     reflect.String,
 						ColumnTypeName: "TestTable",
-						ForeignKey:     ForeignKey{IsForeignKey: true, ForeignKeyTableReference: "TestTable", ForeignKeyColumnReference: "Name"},
+						ForeignKey:     ForeignKey{IsForeignKey: true, ForeignKeyTableReference: "TestTable", ForeignKeyColumnReference: "Name", ForeignKeyField: "Name", ForeignKeyModel: "github.com/plopezm/goedb/database/models.TestTable"},
 						IsComplex:      true,
 					},
 					{
 						Title:          "Ignorable",
+						Field:          "Ignorable",
 						Ignore:         true,
 						ColumnType:     reflect.Bool,
 						ColumnTypeName: "bool",
@@ -107,6 +116,39 @@ func TestParseModel(t *testing.T) {
 	}
 }
 
+func TestIsGenerated(t *testing.T) {
+	type TestTableGenerated struct {
+		ID     uint64 `goedb:"pk,autoincrement"`
+		Status string `goedb:"default='active'"`
+		Desc   string
+	}
+
+	table := ParseModel(&TestTableGenerated{})
+	if table.Columns[1].Default != "'active'" {
+		t.Errorf("ParseModel() Default = %v, want 'active'", table.Columns[1].Default)
+	}
+
+	tests := []struct {
+		name     string
+		instance TestTableGenerated
+		column   int
+		want     bool
+	}{
+		{name: "AutoIncrement", instance: TestTableGenerated{ID: 1}, column: 0, want: true},
+		{name: "DefaultNotSet", instance: TestTableGenerated{}, column: 1, want: true},
+		{name: "DefaultSet", instance: TestTableGenerated{Status: "disabled"}, column: 1, want: false},
+		{name: "WithoutDefault", instance: TestTableGenerated{}, column: 2, want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			value := reflect.ValueOf(tt.instance).Field(tt.column)
+			if got := IsGenerated(table.Columns[tt.column], value); got != tt.want {
+				t.Errorf("IsGenerated() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
 func getGoedbTableTestParser() interface{} {
 
 	type TestTable struct {
@@ -155,7 +197,7 @@ func MockGetModel(name string) (Table, bool) {
 		TestTableWithFKName TestTableWithFK `goedb:"pk,fk=TestTableWithFK(Name)"`
 	}
 
-	if name == "TestTableWithFK2" {
+	if name == ModelKey(reflect.TypeOf(TestTableWithFK2{})) {
 		return ParseModel(&TestTableWithFK2{
 			Name: "ExampleMultiStruct",
 			TestTableWithFKName: TestTableWithFK{
@@ -165,7 +207,7 @@ func MockGetModel(name string) (Table, bool) {
 				Desc:          "testing description",
 			},
 		}), true
-	} else if name == "TestTableWithFK" {
+	} else if name == ModelKey(reflect.TypeOf(TestTableWithFK{})) {
 		return ParseModel(&TestTableWithFK{
 			Name:          "TestTableWithFK-Name",
 			TestTableName: TestTable{ID: 1, Name: "TestTableName-Name-ID"},
@@ -202,3 +244,468 @@ func TestStructToSliceOfAddressesWithRules(t *testing.T) {
 		})
 	}
 }
+
+func TestParseModel_Time(t *testing.T) {
+	type TestTableWithTime struct {
+		ID        int `goedb:"pk,autoincrement"`
+		CreatedAt time.Time
+	}
+
+	table := ParseModel(&TestTableWithTime{})
+	want := Column{Title: "CreatedAt", Field: "CreatedAt", ColumnType: reflect.Struct, ColumnTypeName: "Time", IsTime: true}
+	if !reflect.DeepEqual(table.Columns[1], want) {
+		t.Errorf("ParseModel() time column = %v, want %v", table.Columns[1], want)
+	}
+
+	value := &TestTableWithTime{}
+	addresses := StructToSliceOfAddresses(value)
+	if len(addresses) != 2 {
+		t.Fatalf("StructToSliceOfAddresses() len = %v, want 2", len(addresses))
+	}
+	if err := addresses[1].(utcTime).Scan("2017-11-05 10:30:00+01:00"); err != nil {
+		t.Error(err)
+	}
+	if want := time.Date(2017, 11, 5, 9, 30, 0, 0, time.UTC); !value.CreatedAt.Equal(want) || value.CreatedAt.Location() != time.UTC {
+		t.Errorf("utcTime.Scan() = %v, want %v", value.CreatedAt, want)
+	}
+}
+
+func TestParseModel_Timestamps(t *testing.T) {
+	type TestTableWithTimestamps struct {
+		ID        int        `goedb:"pk,autoincrement"`
+		CreatedAt time.Time  `goedb:"createdAt"`
+		UpdatedAt *time.Time `goedb:"updatedAt"`
+	}
+
+	table := ParseModel(&TestTableWithTimestamps{})
+	if !table.Columns[1].CreatedAt || table.Columns[1].UpdatedAt || !table.Columns[2].UpdatedAt || table.Columns[2].CreatedAt {
+		t.Errorf("ParseModel() timestamp columns = %v", table.Columns)
+	}
+
+	created := time.Date(2017, 11, 5, 9, 30, 0, 0, time.UTC)
+	now := created.Add(time.Hour)
+	value := &TestTableWithTimestamps{CreatedAt: created}
+	SetTimestamp(GetValue(value).Field(1), now, true)
+	SetTimestamp(GetValue(value).Field(2), now, false)
+	if !value.CreatedAt.Equal(created) || value.UpdatedAt == nil || !value.UpdatedAt.Equal(now) {
+		t.Errorf("SetTimestamp() = %v, %v, want %v, %v", value.CreatedAt, value.UpdatedAt, created, now)
+	}
+	value.CreatedAt = time.Time{}
+	SetTimestamp(GetValue(value).Field(1), now, true)
+	if !value.CreatedAt.Equal(now) {
+		t.Errorf("SetTimestamp() of a zero time = %v, want %v", value.CreatedAt, now)
+	}
+}
+
+func TestParseModel_SoftDelete(t *testing.T) {
+	type TestTableWithSoftDelete struct {
+		ID        int        `goedb:"pk,autoincrement"`
+		DeletedAt *time.Time `goedb:"softDelete"`
+	}
+
+	column, ok := GetSoftDeleteColumn(ParseModel(&TestTableWithSoftDelete{}))
+	if !ok || column.Title != "DeletedAt" || !column.IsTime {
+		t.Errorf("GetSoftDeleteColumn() = %v, %v, want the DeletedAt column", column, ok)
+	}
+	if _, ok := GetSoftDeleteColumn(ParseModel(&testNamedSquad{})); ok {
+		t.Errorf("GetSoftDeleteColumn() of a table without softDelete column found one")
+	}
+}
+
+func Test_utcTime_Scan(t *testing.T) {
+	want := time.Date(2017, 11, 5, 9, 30, 0, 0, time.UTC)
+	tests := []struct {
+		name    string
+		src     interface{}
+		want    time.Time
+		wantErr bool
+	}{
+		{name: "Time", src: want.In(time.FixedZone("CET", 3600)), want: want},
+		{name: "Text", src: "2017-11-05 09:30:00", want: want},
+		{name: "Bytes", src: []byte("2017-11-05T09:30:00Z"), want: want},
+		{name: "Unix", src: want.Unix(), want: want},
+		{name: "Null", src: nil, want: time.Time{}},
+		{name: "Invalid", src: "yesterday", wantErr: true},
+		{name: "Unsupported", src: 1.5, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got time.Time
+			err := utcTime{time: &got}.Scan(tt.src)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("utcTime.Scan() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !tt.wantErr && (!got.Equal(tt.want) || got.Location() != time.UTC) {
+				t.Errorf("utcTime.Scan() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseModel_Nullable(t *testing.T) {
+	type TestTableNullable struct {
+		Name     *string
+		Age      *int
+		Nickname sql.NullString
+		Born     *time.Time
+		Email    string `goedb:"notnull"`
+	}
+
+	table := ParseModel(&TestTableNullable{})
+	want := []Column{
+		{Title: "Name", Field: "Name", ColumnType: reflect.String, ColumnTypeName: "string"},
+		{Title: "Age", Field: "Age", ColumnType: reflect.Int, ColumnTypeName: "int"},
+		{Title: "Nickname", Field: "Nickname", ColumnType: reflect.String, ColumnTypeName: "NullString"},
+		{Title: "Born", Field: "Born", ColumnType: reflect.Struct, ColumnTypeName: "Time", IsTime: true},
+		{Title: "Email", Field: "Email", ColumnType: reflect.String, ColumnTypeName: "string", NotNull: true},
+	}
+	if !reflect.DeepEqual(table.Columns, want) {
+		t.Errorf("ParseModel() columns = %v, want %v", table.Columns, want)
+	}
+
+	value := &TestTableNullable{}
+	addresses := StructToSliceOfAddresses(value)
+	if err := addresses[3].(nullTime).Scan("2017-11-05 09:30:00"); err != nil || value.Born == nil {
+		t.Errorf("nullTime.Scan() = %v, %v", value.Born, err)
+	}
+	if err := addresses[3].(nullTime).Scan(nil); err != nil || value.Born != nil {
+		t.Errorf("nullTime.Scan() = %v, %v, want nil", value.Born, err)
+	}
+}
+
+func TestParseModel_NullTypes(t *testing.T) {
+	type TestTableNullTypes struct {
+		S   sql.NullString
+		N64 sql.NullInt64
+		N32 sql.NullInt32
+		N16 sql.NullInt16
+		B   sql.NullByte
+		F   sql.NullFloat64
+		OK  sql.NullBool
+		T   sql.NullTime
+	}
+
+	table := ParseModel(&TestTableNullTypes{})
+	want := []reflect.Kind{reflect.String, reflect.Int64, reflect.Int32, reflect.Int16, reflect.Uint8, reflect.Float64, reflect.Bool, reflect.Struct}
+	for i, column := range table.Columns {
+		if column.ColumnType != want[i] || column.IsTime != (column.Title == "T") {
+			t.Errorf("ParseModel() column %v = %v, want %v", column.Title, column, want[i])
+		}
+	}
+
+	value := &TestTableNullTypes{}
+	address := StructToSliceOfAddresses(value)[7].(sqlNullTime)
+	if err := address.Scan("2017-11-05 09:30:00"); err != nil || !value.T.Valid || value.T.Time != time.Date(2017, 11, 5, 9, 30, 0, 0, time.UTC) {
+		t.Errorf("sqlNullTime.Scan() = %v, %v", value.T, err)
+	}
+	if err := address.Scan(nil); err != nil || value.T.Valid || !value.T.Time.IsZero() {
+		t.Errorf("sqlNullTime.Scan() = %v, %v, want NULL", value.T, err)
+	}
+	if got := DatabaseValue(sql.NullTime{Time: time.Date(2017, 11, 5, 10, 30, 0, 0, time.FixedZone("CET", 3600)), Valid: true}); got != time.Date(2017, 11, 5, 9, 30, 0, 0, time.UTC) {
+		t.Errorf("DatabaseValue() = %v, want the time in UTC", got)
+	}
+	if got := DatabaseValue(sql.NullTime{}); got != nil {
+		t.Errorf("DatabaseValue() = %v, want nil", got)
+	}
+}
+
+type testMoney int64
+
+func (m testMoney) Value() (driver.Value, error) { return int64(m), nil }
+
+func (m *testMoney) Scan(src interface{}) error {
+	*m = testMoney(src.(int64))
+	return nil
+}
+
+type testUUID struct {
+	high, low uint64
+}
+
+func (u *testUUID) Value() (driver.Value, error) { return []byte{}, nil }
+
+func (u *testUUID) Scan(src interface{}) error { return nil }
+
+func Test_splitTag(t *testing.T) {
+	tests := []struct {
+		tag  string
+		want []string
+	}{
+		{tag: "pk,autoincrement", want: []string{"pk", "autoincrement"}},
+		{tag: "type=NUMERIC(12,2),notnull", want: []string{"type=NUMERIC(12,2)", "notnull"}},
+		{tag: "pk,fk=TestTable(Name)", want: []string{"pk", "fk=TestTable(Name)"}},
+		{tag: "default=coalesce(1,2)", want: []string{"default=coalesce(1,2)"}},
+	}
+	for _, tt := range tests {
+		if got := splitTag(tt.tag); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("splitTag(%v) = %v, want %v", tt.tag, got, tt.want)
+		}
+	}
+}
+
+func TestParseModel_CustomTypes(t *testing.T) {
+	type TestTableCustom struct {
+		Amount testMoney `goedb:"type=NUMERIC(12,2),notnull"`
+		Bonus  *testMoney
+		ID     testUUID `goedb:"type=UUID"`
+	}
+
+	table := ParseModel(&TestTableCustom{})
+	want := []Column{
+		{Title: "Amount", Field: "Amount", ColumnType: reflect.Int64, ColumnTypeName: "testMoney", SQLType: "NUMERIC(12,2)", NotNull: true},
+		{Title: "Bonus", Field: "Bonus", ColumnType: reflect.Int64, ColumnTypeName: "testMoney"},
+		{Title: "ID", Field: "ID", ColumnType: reflect.Struct, ColumnTypeName: "testUUID", SQLType: "UUID"},
+	}
+	if !reflect.DeepEqual(table.Columns, want) {
+		t.Errorf("ParseModel() columns = %v, want %v", table.Columns, want)
+	}
+
+	value := &TestTableCustom{Amount: 1250}
+	addresses := StructToSliceOfAddresses(value)
+	if len(addresses) != 3 {
+		t.Fatalf("StructToSliceOfAddresses() len = %v, want 3", len(addresses))
+	}
+	if _, ok := addresses[2].(sql.Scanner); !ok {
+		t.Errorf("StructToSliceOfAddresses() %T is not a sql.Scanner", addresses[2])
+	}
+	if _, ok := FieldValue(GetValue(value).Field(2)).(driver.Valuer); !ok {
+		t.Errorf("FieldValue() is not a driver.Valuer")
+	}
+	if got := FieldValue(GetValue(value).Field(0)); got != testMoney(1250) {
+		t.Errorf("FieldValue() = %v, want 1250", got)
+	}
+	byValue := TestTableCustom{ID: testUUID{high: 1, low: 2}}
+	if got, ok := FieldValue(GetValue(byValue).Field(2)).(*testUUID); !ok || *got != byValue.ID {
+		t.Errorf("FieldValue() of an entity passed by value = %#v, want a *testUUID", got)
+	}
+}
+
+func TestParseModel_JSON(t *testing.T) {
+	type testAddress struct {
+		City string `json:"city"`
+	}
+	type TestTableJSON struct {
+		ID       int                    `goedb:"pk"`
+		Address  testAddress            `goedb:"json"`
+		Settings map[string]interface{} `goedb:"json"`
+		Tags     []string               `goedb:"json"`
+	}
+
+	table := ParseModel(&TestTableJSON{})
+	want := []Column{
+		{Title: "ID", Field: "ID", ColumnType: reflect.Int, ColumnTypeName: "int", PrimaryKey: true},
+		{Title: "Address", Field: "Address", ColumnType: reflect.Struct, ColumnTypeName: "testAddress", IsJSON: true},
+		{Title: "Settings", Field: "Settings", ColumnType: reflect.Map, IsJSON: true},
+		{Title: "Tags", Field: "Tags", ColumnType: reflect.Slice, IsJSON: true},
+	}
+	if !reflect.DeepEqual(table.Columns, want) {
+		t.Errorf("ParseModel() columns = %v, want %v", table.Columns, want)
+	}
+
+	value := &TestTableJSON{ID: 1, Address: testAddress{City: "Madrid"}}
+	if got, err := ColumnValue(table.Columns[1], GetValue(value).Field(1)).(driver.Valuer).Value(); err != nil || got != `{"city":"Madrid"}` {
+		t.Errorf("ColumnValue() = %v, %v", got, err)
+	}
+	if got, err := ColumnValue(table.Columns[3], GetValue(value).Field(3)).(driver.Valuer).Value(); err != nil || got != nil {
+		t.Errorf("ColumnValue() of a nil slice = %v, %v", got, err)
+	}
+
+	read := &TestTableJSON{}
+	addresses := StructToSliceOfAddresses(read)
+	if len(addresses) != 4 {
+		t.Fatalf("StructToSliceOfAddresses() len = %v, want 4", len(addresses))
+	}
+	for i, raw := range []interface{}{[]byte(`{"city":"Madrid"}`), `{"rank":"captain"}`, []byte(`["a","b"]`)} {
+		if err := addresses[i+1].(sql.Scanner).Scan(raw); err != nil {
+			t.Fatalf("Scan() error = %v", err)
+		}
+	}
+	if read.Address.City != "Madrid" || read.Settings["rank"] != "captain" || !reflect.DeepEqual(read.Tags, []string{"a", "b"}) {
+		t.Errorf("Scan() = %+v", read)
+	}
+	if err := addresses[3].(sql.Scanner).Scan(nil); err != nil || read.Tags != nil {
+		t.Errorf("Scan(nil) = %v, %v", read.Tags, err)
+	}
+}
+
+func TestParseModel_Version(t *testing.T) {
+	type TestTableVersion struct {
+		ID      int    `goedb:"pk"`
+		Version uint32 `goedb:"version"`
+	}
+
+	table := ParseModel(&TestTableVersion{})
+	column, ok := GetVersionColumn(table)
+	if !ok || column.Field != "Version" {
+		t.Fatalf("GetVersionColumn() = %v, %v, want the Version column", column, ok)
+	}
+
+	value := &TestTableVersion{ID: 1, Version: 4}
+	field := GetValue(value).Field(1)
+	if got, err := VersionValue(column, field); err != nil || got != 4 {
+		t.Errorf("VersionValue() = %v, %v, want 4", got, err)
+	}
+	IncrementVersion(field)
+	if value.Version != 5 {
+		t.Errorf("IncrementVersion() = %v, want 5", value.Version)
+	}
+	if _, err := VersionValue(column, reflect.ValueOf("4")); err == nil {
+		t.Errorf("VersionValue() of a string, error expected")
+	}
+	if _, ok := GetVersionColumn(ParseModel(&testNamedSquad{})); ok {
+		t.Errorf("GetVersionColumn() of a table without version column found one")
+	}
+}
+
+type testNamedSquad struct {
+	SquadID int    `goedb:"pk,column=id"`
+	Name    string `goedb:"unique"`
+}
+
+func (testNamedSquad) TableName() string {
+	return "squads"
+}
+
+func Test_toSnakeCase(t *testing.T) {
+	tests := map[string]string{
+		"Name":         "name",
+		"ID":           "id",
+		"TroopID":      "troop_id",
+		"HTTPServer":   "http_server",
+		"Address2City": "address2_city",
+		"createdAt":    "created_at",
+	}
+	for name, want := range tests {
+		if got := toSnakeCase(name); got != want {
+			t.Errorf("toSnakeCase(%v) = %v, want %v", name, got, want)
+		}
+	}
+}
+
+func TestGetNamingStrategy(t *testing.T) {
+	naming, err := GetNamingStrategy("snake_case", "app_")
+	if err != nil {
+		t.Fatalf("GetNamingStrategy() error = %v", err)
+	}
+	if got := naming.TableName("SquadMember"); got != "app_squad_member" {
+		t.Errorf("TableName() = %v, want app_squad_member", got)
+	}
+	if got := naming.ColumnName("SquadID"); got != "squad_id" {
+		t.Errorf("ColumnName() = %v, want squad_id", got)
+	}
+	if _, err = GetNamingStrategy("camelCase", ""); err == nil {
+		t.Errorf("GetNamingStrategy() accepted an unknown strategy")
+	}
+}
+
+type testTracker struct {
+	snapshot map[string]interface{}
+}
+
+func TestParseModel_EmbeddedWithoutExportedFields(t *testing.T) {
+	type TrackedSoldier struct {
+		testTracker
+		ID   int `goedb:"pk"`
+		Name string
+	}
+
+	table, err := ParseModelWithNaming(&TrackedSoldier{}, DefaultNaming{})
+	if err != nil {
+		t.Fatalf("ParseModelWithNaming() error = %v", err)
+	}
+	if len(table.Columns) != 3 || !table.Columns[0].Ignore || table.Columns[1].Ignore || table.Columns[2].Ignore {
+		t.Errorf("ParseModelWithNaming() columns = %v, want the embedded struct ignored", table.Columns)
+	}
+	if got := StructToSliceOfAddresses(&TrackedSoldier{}); len(got) != 2 {
+		t.Errorf("StructToSliceOfAddresses() = %v, want the addresses of ID and Name", got)
+	}
+}
+
+func TestParseModelWithNaming_ScalarForeignKey(t *testing.T) {
+	type SquadMember struct {
+		MemberID int `goedb:"pk,autoincrement"`
+		SquadID  int `goedb:"fk=TestNamedSquad(SquadID)"`
+	}
+
+	table, err := ParseModelWithNaming(&SquadMember{}, SnakeCaseNaming{})
+	if err != nil {
+		t.Fatalf("ParseModelWithNaming() error = %v", err)
+	}
+	fk := table.Columns[1].ForeignKey
+	if fk.ForeignKeyTableReference != "test_named_squad" || fk.ForeignKeyColumnReference != "squad_id" {
+		t.Errorf("ParseModelWithNaming() references %v(%v), want test_named_squad(squad_id)", fk.ForeignKeyTableReference, fk.ForeignKeyColumnReference)
+	}
+
+	table = ParseModel(&SquadMember{})
+	if fk = table.Columns[1].ForeignKey; fk.ForeignKeyTableReference != "TestNamedSquad" || fk.ForeignKeyColumnReference != "SquadID" {
+		t.Errorf("ParseModel() references %v(%v), want TestNamedSquad(SquadID)", fk.ForeignKeyTableReference, fk.ForeignKeyColumnReference)
+	}
+}
+
+func TestParseModelWithNaming(t *testing.T) {
+	type SquadMember struct {
+		_        struct{}       `goedb:"table=members"`
+		MemberID int            `goedb:"pk,autoincrement"`
+		FullName string         `goedb:"column=name"`
+		Squad    testNamedSquad `goedb:"fk=testNamedSquad(SquadID)"`
+	}
+
+	table, err := ParseModelWithNaming(&SquadMember{}, SnakeCaseNaming{})
+	if err != nil || table.Name != "members" {
+		t.Errorf("ParseModelWithNaming() Name = %v, %v, want members", table.Name, err)
+	}
+	if !table.Columns[0].Ignore {
+		t.Errorf("ParseModelWithNaming() the blank field is not ignored")
+	}
+	titles := []string{table.Columns[1].Title, table.Columns[2].Title, table.Columns[3].Title}
+	if !reflect.DeepEqual(titles, []string{"member_id", "name", "squad"}) {
+		t.Errorf("ParseModelWithNaming() titles = %v", titles)
+	}
+	if table.Columns[2].Field != "FullName" {
+		t.Errorf("ParseModelWithNaming() Field = %v, want FullName", table.Columns[2].Field)
+	}
+	wantFK := ForeignKey{
+		IsForeignKey:              true,
+		ForeignKeyTableReference:  "squads",
+		ForeignKeyColumnReference: "id",
+		ForeignKeyField:           "SquadID",
+		ForeignKeyModel:           ModelKey(reflect.TypeOf(testNamedSquad{})),
+	}
+	if !reflect.DeepEqual(table.Columns[3].ForeignKey, wantFK) {
+		t.Errorf("ParseModelWithNaming() ForeignKey = %v, want %v", table.Columns[3].ForeignKey, wantFK)
+	}
+	if len(StructToSliceOfAddresses(&SquadMember{})) != 4 {
+		t.Errorf("StructToSliceOfAddresses() the blank field is not skipped")
+	}
+
+	squads, err := ParseModelWithNaming(&testNamedSquad{}, LowerCaseNaming{})
+	if err != nil || squads.Name != "squads" || squads.Columns[0].Title != "id" || squads.Columns[1].Title != "name" {
+		t.Errorf("ParseModelWithNaming() = %v, %v", squads, err)
+	}
+}
+
+func TestParseModelWithNaming_UnsupportedFields(t *testing.T) {
+	type TestTableWithPointer struct {
+		ID    int `goedb:"pk"`
+		Squad *testNamedSquad
+	}
+	type TestTableWithStruct struct {
+		ID    int `goedb:"pk"`
+		Squad testNamedSquad
+	}
+	type TestTableIgnored struct {
+		ID    int             `goedb:"pk"`
+		Squad *testNamedSquad `goedb:"ignore"`
+	}
+
+	if _, err := ParseModelWithNaming(&TestTableWithPointer{}, DefaultNaming{}); err == nil || !strings.Contains(err.Error(), "Squad") {
+		t.Errorf("ParseModelWithNaming() of a pointer to a struct, error = %v", err)
+	}
+	if _, err := ParseModelWithNaming(&TestTableWithStruct{}, DefaultNaming{}); err == nil {
+		t.Errorf("ParseModelWithNaming() of a struct without foreign key, error expected")
+	}
+	if _, err := ParseModelWithNaming(&TestTableIgnored{}, DefaultNaming{}); err != nil {
+		t.Errorf("ParseModelWithNaming() of an ignored field, error = %v", err)
+	}
+}
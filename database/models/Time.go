@@ -0,0 +1,92 @@
+package models
+
+import (
+	"database/sql"
+	"errors"
+	"reflect"
+	"time"
+)
+
+var timeType = reflect.TypeOf(time.Time{})
+var timePtrType = reflect.TypeOf(&time.Time{})
+var sqlNullTimeType = reflect.TypeOf(sql.NullTime{})
+
+// timeFormats are the text formats used by SQLite3 to store the times
+var timeFormats = []string{
+	"2006-01-02 15:04:05.999999999-07:00",
+	"2006-01-02T15:04:05.999999999-07:00",
+	"2006-01-02 15:04:05.999999999",
+	"2006-01-02T15:04:05.999999999",
+	"2006-01-02 15:04:05",
+	"2006-01-02T15:04:05",
+	"2006-01-02 15:04",
+	"2006-01-02T15:04",
+	"2006-01-02",
+	time.RFC3339Nano,
+}
+
+// IsTime returns true if the type is time.Time, it is stored as a column instead of a relation
+func IsTime(typ reflect.Type) bool {
+	return typ == timeType
+}
+
+// utcTime scans a time from the database into a time.Time in UTC.
+// The times stored as text or as a unix timestamp are parsed too
+type utcTime struct {
+	time *time.Time
+}
+
+func (t utcTime) Scan(src interface{}) (err error) {
+	switch value := src.(type) {
+	case time.Time:
+		*t.time = value.UTC()
+	case nil:
+		*t.time = time.Time{}
+	case int64:
+		*t.time = time.Unix(value, 0).UTC()
+	case []byte:
+		*t.time, err = parseTime(string(value))
+	case string:
+		*t.time, err = parseTime(value)
+	default:
+		err = errors.New("Cannot scan " + reflect.TypeOf(src).String() + " into time.Time")
+	}
+	return err
+}
+
+// nullTime scans a nullable time, the pointer is set to nil if the value is NULL
+type nullTime struct {
+	time **time.Time
+}
+
+func (t nullTime) Scan(src interface{}) error {
+	if src == nil {
+		*t.time = nil
+		return nil
+	}
+	value := new(time.Time)
+	if err := (utcTime{time: value}).Scan(src); err != nil {
+		return err
+	}
+	*t.time = value
+	return nil
+}
+
+// sqlNullTime scans a nullable time into a sql.NullTime, parsing the times stored as text like utcTime
+type sqlNullTime struct {
+	time *sql.NullTime
+}
+
+func (t sqlNullTime) Scan(src interface{}) error {
+	t.time.Valid = src != nil
+	return utcTime{time: &t.time.Time}.Scan(src)
+}
+
+func parseTime(value string) (time.Time, error) {
+	for _, format := range timeFormats {
+		if parsed, err := time.ParseInLocation(format, value, time.UTC); err == nil {
+			return parsed.UTC(), nil
+		}
+	}
+	return time.Time{}, errors.New("Cannot parse time " + value)
+}
@@ -0,0 +1,114 @@
+package models
+
+import (
+	"database/sql"
+	"errors"
+	"reflect"
+	"time"
+)
+
+// ColumnAddress returns the address used to scan the column into the field
+func ColumnAddress(column Column, field reflect.Value) interface{} {
+	if column.IsJSON {
+		return jsonColumn{value: field}
+	}
+	return FieldAddress(field)
+}
+
+// ColumnValue returns the value of the field written into the column
+func ColumnValue(column Column, field reflect.Value) interface{} {
+	if column.IsJSON {
+		return jsonColumn{value: field}
+	}
+	return FieldValue(field)
+}
+
+// FieldAddress returns the address used to scan a column into the field
+func FieldAddress(field reflect.Value) interface{} {
+	if IsTime(field.Type()) {
+		return utcTime{time: field.Addr().Interface().(*time.Time)}
+	}
+	if field.Type() == timePtrType {
+		return nullTime{time: field.Addr().Interface().(**time.Time)}
+	}
+	if field.Type() == sqlNullTimeType {
+		return sqlNullTime{time: field.Addr().Interface().(*sql.NullTime)}
+	}
+	return field.Addr().Interface()
+}
+
+// FieldValue returns the value of the field written into the database. The value of the types implementing
+// driver.Valuer with a pointer receiver is the address of the field or, if the entity was passed by value,
+// the address of a copy
+func FieldValue(field reflect.Value) interface{} {
+	if !field.Type().Implements(valuerType) && reflect.PtrTo(field.Type()).Implements(valuerType) {
+		if field.CanAddr() {
+			return field.Addr().Interface()
+		}
+		copied := reflect.New(field.Type())
+		copied.Elem().Set(field)
+		return copied.Interface()
+	}
+	return DatabaseValue(field.Interface())
+}
+
+// DatabaseValue returns the value written into the database, times are stored in UTC and nil pointers are NULL
+func DatabaseValue(value interface{}) interface{} {
+	switch t := value.(type) {
+	case time.Time:
+		return t.UTC()
+	case *time.Time:
+		if t == nil {
+			return nil
+		}
+		return t.UTC()
+	case sql.NullTime:
+		if !t.Valid {
+			return nil
+		}
+		return t.Time.UTC()
+	}
+	return value
+}
+
+// SetTimestamp sets a time.Time or *time.Time field, if onlyZero is true a field already set is kept.
+// It does nothing if the field cannot be set
+func SetTimestamp(field reflect.Value, timestamp time.Time, onlyZero bool) {
+	if !field.CanSet() {
+		return
+	}
+	switch {
+	case IsTime(field.Type()):
+		if !onlyZero || field.Interface().(time.Time).IsZero() {
+			field.Set(reflect.ValueOf(timestamp))
+		}
+	case field.Type() == timePtrType:
+		if !onlyZero || field.IsNil() {
+			field.Set(reflect.ValueOf(&timestamp))
+		}
+	}
+}
+
+// VersionValue returns the value of the version field of an entity, an error is returned if it is not an integer
+func VersionValue(column Column, field reflect.Value) (int64, error) {
+	switch field.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return field.Int(), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return int64(field.Uint()), nil
+	}
+	return 0, errors.New("Version column " + column.Title + " must be an integer")
+}
+
+// IncrementVersion adds one to the version field of an entity, it does nothing if the field cannot be set
+func IncrementVersion(field reflect.Value) {
+	if !field.CanSet() {
+		return
+	}
+	switch field.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		field.SetInt(field.Int() + 1)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		field.SetUint(field.Uint() + 1)
+	}
+}
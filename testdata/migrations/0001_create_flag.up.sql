@@ -0,0 +1 @@
+CREATE TABLE flag (ID INTEGER PRIMARY KEY, Name VARCHAR UNIQUE)
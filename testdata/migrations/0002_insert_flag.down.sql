@@ -0,0 +1 @@
+DELETE FROM flag WHERE ID = 1
@@ -0,0 +1 @@
+INSERT INTO flag (ID, Name) VALUES (1, 'Normandy')